@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/clarke94/crawler"
+	"github.com/clarke94/crawler/page"
 )
 
 const maxDepth = 5
@@ -26,12 +27,9 @@ func main() {
 }
 
 // CustomEnforcer provides a custom enforcer with max depth.
-type CustomEnforcer struct {
-	MaxDepth int
-}
+type CustomEnforcer struct{}
 
-// Enforce enforces a max depth less than 5.
-func (c *CustomEnforcer) Enforce(_ map[url.URL]bool, _ *url.URL) bool {
-	c.MaxDepth++
-	return c.MaxDepth < maxDepth
+// Enforce enforces a max depth less than 5 for links not yet visited.
+func (c *CustomEnforcer) Enforce(data map[url.URL]bool, link *page.Link) bool {
+	return link.Depth < maxDepth && !data[*link.URL]
 }
@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"github.com/clarke94/crawler"
+	"github.com/clarke94/crawler/page"
 )
 
 func main() {
@@ -33,6 +34,6 @@ func (c CustomLogger) Error(err error) {
 }
 
 // Info prints custom info.
-func (c CustomLogger) Info(visited *url.URL, found []*url.URL) {
-	fmt.Printf("Custom: %s %v", visited.String(), found)
+func (c CustomLogger) Info(visited *page.Link, resp *page.Response, found []*url.URL) {
+	fmt.Printf("Custom: %s from %v %d %v", visited.URL.String(), visited.Parent, resp.StatusCode, found)
 }
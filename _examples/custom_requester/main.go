@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/clarke94/crawler"
+	"github.com/clarke94/crawler/page"
 )
 
 func main() {
@@ -38,7 +39,7 @@ func (c CustomRequester) Request(_ context.Context, rawURL string, _ io.Reader)
 }
 
 // Do sends the request with a custom client.
-func (c *CustomRequester) Do(req *http.Request) (io.ReadCloser, error) {
+func (c *CustomRequester) Do(req *http.Request) (*page.Response, error) {
 	client := &http.Client{}
 
 	resp, err := client.Do(req)
@@ -46,5 +47,11 @@ func (c *CustomRequester) Do(req *http.Request) (io.ReadCloser, error) {
 		return nil, err
 	}
 
-	return resp.Body, nil
+	return &page.Response{
+		Request:    req,
+		URL:        resp.Request.URL,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       resp.Body,
+	}, nil
 }
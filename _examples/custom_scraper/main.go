@@ -2,13 +2,12 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/clarke94/crawler"
+	"github.com/clarke94/crawler/page"
 	"golang.org/x/net/html"
 )
 
@@ -32,12 +31,12 @@ func main() {
 type CustomScraper struct{}
 
 // Scrape scrapes all text and prints it to the console.
-func (c CustomScraper) Scrape(_ *http.Request, closer io.ReadCloser) ([]*url.URL, error) {
-	defer closer.Close()
+func (c CustomScraper) Scrape(_ *page.Link, resp *page.Response) ([]*url.URL, error) {
+	defer resp.Body.Close()
 
 	var links []*url.URL
 
-	z := html.NewTokenizer(closer)
+	z := html.NewTokenizer(resp.Body)
 
 	for {
 		tt := z.Next()
@@ -6,6 +6,7 @@ import (
 
 	"github.com/clarke94/crawler"
 	"github.com/clarke94/crawler/enforce/samedomainonce"
+	"github.com/clarke94/crawler/page"
 )
 
 const maxDepth = 10
@@ -35,17 +36,16 @@ type CustomEnforcer struct {
 // NewEnforcer initializes a new custom enforcer.
 func NewEnforcer() *CustomEnforcer {
 	return &CustomEnforcer{
-		MaxDepth: 0,
+		MaxDepth: maxDepth,
 		enforcer: samedomainonce.New(),
 	}
 }
 
 // Enforce enforces a max depth less than 10 for the same domain that is only visited once.
-func (c *CustomEnforcer) Enforce(data map[url.URL]bool, u *url.URL) bool {
-	c.MaxDepth++
-	if c.MaxDepth >= maxDepth {
+func (c *CustomEnforcer) Enforce(data map[url.URL]bool, link *page.Link) bool {
+	if link.Depth >= c.MaxDepth {
 		return false
 	}
 
-	return c.enforcer.Enforce(data, u)
+	return c.enforcer.Enforce(data, link)
 }
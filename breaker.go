@@ -0,0 +1,141 @@
+package crawler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+)
+
+// BreakerMode is what happens to the URLs of a host while its circuit breaker is open.
+type BreakerMode int
+
+const (
+	// BreakerDefer holds the URLs of a host back until its circuit breaker half-opens.
+	BreakerDefer BreakerMode = iota
+	// BreakerFailFast fails the URLs of a host with ErrCircuitOpen without sending a request.
+	BreakerFailFast
+)
+
+// breakerState is the state of the circuit breaker of a host.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a circuit breaker for each host. A host is opened after threshold
+// consecutive failed requests and no requests are sent to it until the cooldown
+// is over, then it is half-opened and a single request is sent as a probe.
+// The host is closed if the probe succeeds, otherwise it is opened again.
+// A failed request is an error from the Requester or a 5xx response.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	mode      BreakerMode
+	hosts     map[string]*hostBreaker
+}
+
+// hostBreaker is the circuit breaker state of a single host.
+type hostBreaker struct {
+	state    breakerState
+	failures int
+	until    time.Time
+	probing  bool
+}
+
+// newBreaker initializes a new breaker, a threshold of zero never opens a host.
+func newBreaker(threshold int, cooldown time.Duration, mode BreakerMode) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		mode:      mode,
+		hosts:     map[string]*hostBreaker{},
+	}
+}
+
+// ready reports whether a request can be sent to the host, it returns
+// when the host half-opens if it is open.
+func (b *breaker) ready(host string, now time.Time) (time.Time, bool) {
+	h, ok := b.hosts[host]
+	if !ok {
+		return time.Time{}, true
+	}
+
+	switch h.state {
+	case breakerOpen:
+		return h.until, !now.Before(h.until)
+	case breakerHalfOpen:
+		return time.Time{}, !h.probing
+	default:
+		return time.Time{}, true
+	}
+}
+
+// acquire reports whether a request can be sent to the host, half-opening an open
+// host once its cooldown is over. It reports whether the request is the probe.
+func (b *breaker) acquire(host string, now time.Time) (ok, probe bool) {
+	if _, ok := b.ready(host, now); !ok {
+		return false, false
+	}
+
+	if h, ok := b.hosts[host]; ok && h.state != breakerClosed {
+		h.state = breakerHalfOpen
+		h.probing = true
+
+		return true, true
+	}
+
+	return true, false
+}
+
+// release gives up the probe of a half-open host that was not sent,
+// so the next request to the host is the probe.
+func (b *breaker) release(host string) {
+	if h, ok := b.hosts[host]; ok && h.state == breakerHalfOpen {
+		h.probing = false
+	}
+}
+
+// observe records whether the request to the host failed.
+func (b *breaker) observe(host string, resp *page.Response, err error, now time.Time) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	h, ok := b.hosts[host]
+	if !ok {
+		h = &hostBreaker{}
+		b.hosts[host] = h
+	}
+
+	failed := err != nil || (resp != nil && resp.StatusCode >= http.StatusInternalServerError)
+
+	switch h.state {
+	case breakerClosed:
+		if !failed {
+			h.failures = 0
+
+			return
+		}
+
+		if h.failures++; h.failures >= b.threshold {
+			h.state = breakerOpen
+			h.until = now.Add(b.cooldown)
+		}
+	case breakerHalfOpen:
+		h.probing = false
+
+		if !failed {
+			h.state = breakerClosed
+			h.failures = 0
+
+			return
+		}
+
+		h.state = breakerOpen
+		h.until = now.Add(b.cooldown)
+	}
+}
@@ -0,0 +1,266 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBreaker_acquire(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	failed := &page.Response{StatusCode: http.StatusBadGateway}
+	ok := &page.Response{StatusCode: http.StatusOK}
+
+	tests := []struct {
+		name           string
+		givenResponses []*page.Response
+		givenErrs      []error
+		givenProbe     *page.Response
+		givenRelease   bool
+		givenAfter     time.Duration
+		want           []bool
+	}{
+		{
+			name:           "expect closed given failures below the threshold",
+			givenResponses: []*page.Response{failed, nil},
+			givenErrs:      []error{nil, syscall.ECONNREFUSED},
+			givenAfter:     0,
+			want:           []bool{true, true},
+		},
+		{
+			name:           "expect closed given a success resets the failures",
+			givenResponses: []*page.Response{failed, nil, ok, failed},
+			givenErrs:      []error{nil, syscall.ECONNREFUSED, nil, nil},
+			givenAfter:     0,
+			want:           []bool{true, true},
+		},
+		{
+			name:           "expect open given consecutive failures",
+			givenResponses: []*page.Response{failed, failed, failed},
+			givenErrs:      []error{nil, nil, nil},
+			givenAfter:     time.Second,
+			want:           []bool{false, false},
+		},
+		{
+			name:           "expect a single probe given the cooldown is over",
+			givenResponses: []*page.Response{failed, failed, failed},
+			givenErrs:      []error{nil, nil, nil},
+			givenAfter:     time.Minute,
+			want:           []bool{true, false},
+		},
+		{
+			name:           "expect closed given the probe succeeds",
+			givenResponses: []*page.Response{failed, failed, failed},
+			givenErrs:      []error{nil, nil, nil},
+			givenProbe:     ok,
+			givenAfter:     time.Minute,
+			want:           []bool{true, true},
+		},
+		{
+			name:           "expect another probe given the probe is released",
+			givenResponses: []*page.Response{failed, failed, failed},
+			givenErrs:      []error{nil, nil, nil},
+			givenRelease:   true,
+			givenAfter:     time.Minute,
+			want:           []bool{true, false},
+		},
+		{
+			name:           "expect open again given the probe fails",
+			givenResponses: []*page.Response{failed, failed, failed},
+			givenErrs:      []error{nil, nil, nil},
+			givenProbe:     failed,
+			givenAfter:     time.Minute,
+			want:           []bool{false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(3, time.Minute, BreakerDefer)
+
+			for i, resp := range tt.givenResponses {
+				b.observe("localhost", resp, tt.givenErrs[i], now)
+			}
+
+			at := now.Add(tt.givenAfter)
+
+			if tt.givenProbe != nil {
+				if ok, _ := b.acquire("localhost", at); !ok {
+					t.Fatal("expected probe to be acquired")
+				}
+
+				b.observe("localhost", tt.givenProbe, nil, at)
+			}
+
+			if tt.givenRelease {
+				if ok, probe := b.acquire("localhost", at); !ok || !probe {
+					t.Fatal("expected probe to be acquired")
+				}
+
+				b.release("localhost")
+			}
+
+			var got []bool
+
+			for range tt.want {
+				ok, _ := b.acquire("localhost", at)
+				got = append(got, ok)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithCircuitBreaker_Probe(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.URL.Path != "" {
+			return nil, nil
+		}
+
+		// The default enforcer rejects /a?page=1 as a duplicate of /a.
+		return []*url.URL{
+			testutil.URLMustParse("http://localhost/a"),
+			testutil.URLMustParse("http://localhost/a?page=1"),
+			testutil.URLMustParse("http://localhost/b"),
+		}, nil
+	})
+
+	tests := []struct {
+		name      string
+		givenMode BreakerMode
+		wantSent  int
+	}{
+		{
+			name:      "expect the next URL probed given a duplicate URL took the probe",
+			givenMode: BreakerDefer,
+			wantSent:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			requester := &mockStepRequester{GivenSteps: map[string][]mockStep{
+				"/a": {{Status: http.StatusInternalServerError}},
+			}}
+
+			c := New(
+				WithConcurrency(1),
+				WithCircuitBreaker(1, 20*time.Millisecond, tt.givenMode),
+				WithRequester(requester),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			)
+
+			_, err := c.CrawlContext(ctx, testutil.URLMustParse("http://localhost"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(requester.sent, tt.wantSent) {
+				t.Error(cmp.Diff(requester.sent, tt.wantSent))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithCircuitBreaker(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.URL.Path != "" {
+			return nil, nil
+		}
+
+		var urls []*url.URL
+
+		for i := 1; i <= 5; i++ {
+			urls = append(urls, link.URL.ResolveReference(&url.URL{Path: fmt.Sprintf("/%d", i)}))
+		}
+
+		return urls, nil
+	})
+
+	tests := []struct {
+		name          string
+		givenCooldown time.Duration
+		givenMode     BreakerMode
+		wantSent      int
+		wantErrs      int
+		wantOpen      int
+	}{
+		{
+			name:          "expect URLs failed without a request given fail fast",
+			givenCooldown: time.Minute,
+			givenMode:     BreakerFailFast,
+			wantSent:      3,
+			wantErrs:      5,
+			wantOpen:      3,
+		},
+		{
+			name:          "expect URLs crawled after a successful probe given defer",
+			givenCooldown: 20 * time.Millisecond,
+			givenMode:     BreakerDefer,
+			wantSent:      6,
+			wantErrs:      2,
+			wantOpen:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &mockStepRequester{GivenSteps: map[string][]mockStep{
+				"/1": {{Err: syscall.ECONNREFUSED}},
+				"/2": {{Err: syscall.ECONNREFUSED}},
+			}}
+
+			c := New(
+				WithConcurrency(1),
+				WithCircuitBreaker(2, tt.givenCooldown, tt.givenMode),
+				WithRequester(requester),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			)
+
+			err := c.Crawl(testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, ErrRequester, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrRequester, cmpopts.EquateErrors()))
+			}
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected Errors, got %v", err)
+			}
+
+			var open int
+
+			for _, e := range errs {
+				if errors.Is(e, ErrCircuitOpen) {
+					open++
+				}
+			}
+
+			if !cmp.Equal(requester.sent, tt.wantSent) {
+				t.Error(cmp.Diff(requester.sent, tt.wantSent))
+			}
+
+			if !cmp.Equal(len(errs), tt.wantErrs) {
+				t.Error(cmp.Diff(len(errs), tt.wantErrs))
+			}
+
+			if !cmp.Equal(open, tt.wantOpen) {
+				t.Error(cmp.Diff(open, tt.wantOpen))
+			}
+		})
+	}
+}
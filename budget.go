@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// budget is the most a crawl, or a single host of a crawl, is allowed to use.
+// A zero value is unlimited.
+type budget struct {
+	pages    int
+	bytes    int64
+	duration time.Duration
+}
+
+// usage is what a crawl, or a single host of a crawl, has used of its budget.
+type usage struct {
+	pages int
+	bytes int64
+	start time.Time
+}
+
+// exhausted returns why the budget has been used up, or nil if it has not.
+func (b budget) exhausted(u *usage, now time.Time) error {
+	switch {
+	case b.pages > 0 && u.pages >= b.pages:
+		return errors.Errorf("max pages of %d reached", b.pages)
+	case b.bytes > 0 && u.bytes >= b.bytes:
+		return errors.Errorf("max bytes of %d reached", b.bytes)
+	case b.duration > 0 && !u.start.IsZero() && now.Sub(u.start) >= b.duration:
+		return errors.Errorf("max duration of %v reached", b.duration)
+	}
+
+	return nil
+}
+
+// meter counts the bytes read from a response body against the run and its host,
+// and in the bytes read of the response.
+type meter struct {
+	io.ReadCloser
+	run  *run
+	host string
+	read *int64
+}
+
+// Read implements io.Reader.
+func (m *meter) Read(p []byte) (int, error) {
+	n, err := m.ReadCloser.Read(p)
+	m.run.read(m.host, m.read, n)
+
+	return n, err
+}
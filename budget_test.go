@@ -0,0 +1,382 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBudget_exhausted(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		givenBudget budget
+		givenUsage  *usage
+		wantErr     bool
+	}{
+		{
+			name:        "expect not exhausted given an unlimited budget",
+			givenBudget: budget{},
+			givenUsage:  &usage{pages: 100, bytes: 100, start: now.Add(-time.Hour)},
+			wantErr:     false,
+		},
+		{
+			name:        "expect not exhausted given usage below the budget",
+			givenBudget: budget{pages: 2, bytes: 20, duration: time.Minute},
+			givenUsage:  &usage{pages: 1, bytes: 10, start: now.Add(-time.Second)},
+			wantErr:     false,
+		},
+		{
+			name:        "expect exhausted given max pages reached",
+			givenBudget: budget{pages: 2},
+			givenUsage:  &usage{pages: 2},
+			wantErr:     true,
+		},
+		{
+			name:        "expect exhausted given max bytes reached",
+			givenBudget: budget{bytes: 20},
+			givenUsage:  &usage{bytes: 21},
+			wantErr:     true,
+		},
+		{
+			name:        "expect exhausted given max duration over",
+			givenBudget: budget{duration: time.Minute},
+			givenUsage:  &usage{start: now.Add(-time.Minute)},
+			wantErr:     true,
+		},
+		{
+			name:        "expect not exhausted given max duration not started",
+			givenBudget: budget{duration: time.Minute},
+			givenUsage:  &usage{},
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.givenBudget.exhausted(tt.givenUsage, now)
+			if !cmp.Equal(err != nil, tt.wantErr) {
+				t.Error(cmp.Diff(err != nil, tt.wantErr))
+			}
+		})
+	}
+}
+
+func TestCrawler_Budget(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenOptions []Option
+		want         *Report
+		wantErr      error
+	}{
+		{
+			name:         "expect crawl stopped given max pages reached",
+			givenOptions: []Option{WithMaxPages(3)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+				},
+			},
+			wantErr: ErrBudget,
+		},
+		{
+			name:         "expect hosts skipped given max pages per host reached",
+			givenOptions: []Option{WithMaxPagesPerHost(2)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+					testutil.URLMustParse("http://b.localhost/1"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "expect crawl stopped given max bytes reached",
+			givenOptions: []Option{WithMaxBytes(25)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+				},
+			},
+			wantErr: ErrBudget,
+		},
+		{
+			name:         "expect hosts skipped given max bytes per host reached",
+			givenOptions: []Option{WithMaxBytesPerHost(15)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+					testutil.URLMustParse("http://b.localhost/1"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "expect no error given the crawl finished with max pages reached",
+			givenOptions: []Option{WithMaxPages(6)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+					testutil.URLMustParse("http://a.localhost/2"),
+					testutil.URLMustParse("http://b.localhost/1"),
+					testutil.URLMustParse("http://b.localhost/2"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "expect every URL crawled given budgets not reached",
+			givenOptions: []Option{WithMaxPages(100), WithMaxBytes(1000), WithMaxDuration(time.Minute)},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://a.localhost"),
+					testutil.URLMustParse("http://b.localhost"),
+					testutil.URLMustParse("http://a.localhost/1"),
+					testutil.URLMustParse("http://a.localhost/2"),
+					testutil.URLMustParse("http://b.localhost/1"),
+					testutil.URLMustParse("http://b.localhost/2"),
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{
+				WithConcurrency(1),
+				WithRequester(&mockBodyRequester{GivenBody: "0123456789"}),
+				WithScraper(mockBodyScraper{GivenPaths: []string{"/1", "/2"}}),
+				WithLogger(mockLogger{}),
+			}, tt.givenOptions...)
+
+			got, err := New(options...).CrawlContext(
+				context.Background(),
+				testutil.URLMustParse("http://a.localhost"),
+				testutil.URLMustParse("http://b.localhost"),
+			)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got, tt.want, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.want, ignoreStats))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithMaxDuration(t *testing.T) {
+	c := New(
+		WithConcurrency(1),
+		WithMaxDuration(50*time.Millisecond),
+		WithRequester(&mockBodyRequester{GivenBody: "0123456789", GivenDelay: 20 * time.Millisecond}),
+		WithScraper(mockBodyScraper{GivenPaths: []string{"/1", "/2", "/3", "/4", "/5", "/6"}}),
+		WithLogger(mockLogger{}),
+	)
+
+	got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+	if !errors.Is(err, ErrBudget) {
+		t.Errorf("expected %v, got %v", ErrBudget, err)
+	}
+
+	if len(got.Visited) >= 7 {
+		t.Errorf("expected the crawl stopped before every URL was visited, visited %d", len(got.Visited))
+	}
+}
+
+func TestCrawler_Budget_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenOptions []Option
+		wantSent     int
+		wantRetried  int
+		wantFailures int
+		wantErr      error
+	}{
+		{
+			name:         "expect retry sent given it is within max pages",
+			givenOptions: []Option{WithMaxPages(4)},
+			wantSent:     3,
+			wantRetried:  1,
+			wantFailures: 0,
+			wantErr:      nil,
+		},
+		{
+			name:         "expect no error given the retry uses the last of max pages",
+			givenOptions: []Option{WithMaxPages(3)},
+			wantSent:     3,
+			wantRetried:  1,
+			wantFailures: 0,
+			wantErr:      nil,
+		},
+		{
+			name:         "expect the failed attempt recorded given a retry refused by max pages",
+			givenOptions: []Option{WithMaxPages(2)},
+			wantSent:     2,
+			wantRetried:  1,
+			wantFailures: 1,
+			wantErr:      ErrBudget,
+		},
+		{
+			name:         "expect the failed attempt recorded given a retry refused by max pages per host",
+			givenOptions: []Option{WithMaxPagesPerHost(2)},
+			wantSent:     2,
+			wantRetried:  1,
+			wantFailures: 1,
+			wantErr:      ErrRequester,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &mockStepRequester{GivenSteps: map[string][]mockStep{
+				"/1": {{Status: http.StatusInternalServerError}},
+			}}
+
+			options := append([]Option{
+				WithConcurrency(1),
+				WithRetryPolicy(RetryWithBackoff(2, time.Millisecond, time.Millisecond)),
+				WithRequester(requester),
+				WithScraper(mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+					if link.Depth > 0 {
+						return nil, nil
+					}
+
+					return []*url.URL{testutil.URLMustParse("http://localhost/1")}, nil
+				})),
+				WithLogger(mockLogger{}),
+			}, tt.givenOptions...)
+
+			got, err := New(options...).CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(requester.sent, tt.wantSent) {
+				t.Error(cmp.Diff(requester.sent, tt.wantSent))
+			}
+
+			if !cmp.Equal(got.Retried, tt.wantRetried) {
+				t.Error(cmp.Diff(got.Retried, tt.wantRetried))
+			}
+
+			if !cmp.Equal(got.Stats.Failures, tt.wantFailures) {
+				t.Error(cmp.Diff(got.Stats.Failures, tt.wantFailures))
+			}
+		})
+	}
+}
+
+func TestCrawler_Budget_ResumeFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenMax    int
+		wantVisited int
+		wantErr     error
+	}{
+		{
+			name:        "expect resumed crawl stopped given the budget was used before the checkpoint",
+			givenMax:    2,
+			wantVisited: 2,
+			wantErr:     ErrBudget,
+		},
+		{
+			name:        "expect resumed crawl to use what is left of a larger budget",
+			givenMax:    3,
+			wantVisited: 3,
+			wantErr:     ErrBudget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			newCrawler := func(max int) *Crawler {
+				return New(
+					WithConcurrency(1),
+					WithMaxPages(max),
+					WithCheckpoint(dir, 0),
+					WithRequester(&mockBodyRequester{GivenBody: "0123456789"}),
+					WithScraper(mockBodyScraper{GivenPaths: []string{"/1", "/2", "/3", "/4"}}),
+					WithLogger(mockLogger{}),
+				)
+			}
+
+			_, err := newCrawler(2).CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, ErrBudget, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrBudget, cmpopts.EquateErrors()))
+			}
+
+			got, err := newCrawler(tt.givenMax).ResumeFrom(context.Background(), dir)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(len(got.Visited), tt.wantVisited) {
+				t.Error(cmp.Diff(len(got.Visited), tt.wantVisited))
+			}
+		})
+	}
+}
+
+// mockBodyRequester responds to every request with the given body after the given delay.
+type mockBodyRequester struct {
+	GivenBody  string
+	GivenDelay time.Duration
+}
+
+func (m *mockBodyRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m *mockBodyRequester) Do(req *http.Request) (*page.Response, error) {
+	time.Sleep(m.GivenDelay)
+
+	return &page.Response{
+		Request:    req,
+		URL:        req.URL,
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(m.GivenBody)),
+	}, nil
+}
+
+// mockBodyScraper reads the body and returns the given paths on the host of a seed.
+type mockBodyScraper struct {
+	GivenPaths []string
+}
+
+func (m mockBodyScraper) Scrape(link *page.Link, resp *page.Response) ([]*url.URL, error) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if link.Depth > 0 {
+		return nil, nil
+	}
+
+	urls := make([]*url.URL, 0, len(m.GivenPaths))
+
+	for _, path := range m.GivenPaths {
+		urls = append(urls, &url.URL{Scheme: link.URL.Scheme, Host: link.URL.Host, Path: path})
+	}
+
+	return urls, nil
+}
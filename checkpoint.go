@@ -0,0 +1,380 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+	"github.com/pkg/errors"
+)
+
+// ErrCheckpoint is the annotated error that is wrapped with
+// the error from writing or reading a checkpoint.
+var ErrCheckpoint = errors.New("checkpoint error")
+
+var (
+	errNoSnapshot = errors.New("frontier does not implement Snapshotter or Persister")
+	errPersisted  = errors.New("checkpoint frontier was persisted, frontier does not implement Persister")
+)
+
+const (
+	checkpointFile     = "checkpoint.json"
+	checkpointFilePerm = 0o600
+	checkpointDirPerm  = 0o700
+)
+
+// Snapshotter is implemented by a Frontier that can list its links without
+// removing them, in the order that pushing them to an empty Frontier of the
+// same kind restores it. WithCheckpoint requires a Frontier that implements
+// Snapshotter or Persister.
+type Snapshotter interface {
+	Snapshot() ([]*page.Link, error)
+}
+
+// checkpoint is the state of a crawl written by WithCheckpoint.
+type checkpoint struct {
+	Visited   []string                   `json:"visited"`
+	Pending   []*page.Link               `json:"pending"`
+	Persisted bool                       `json:"persisted"`
+	Report    []string                   `json:"report"`
+	Retried   int                        `json:"retried"`
+	Crawled   int                        `json:"crawled"`
+	Errors    []checkpointError          `json:"errors"`
+	Usage     checkpointUsage            `json:"usage"`
+	Hosts     map[string]checkpointUsage `json:"hosts"`
+	Stats     checkpointStats            `json:"stats"`
+}
+
+// checkpointStats are the response counts and latency samples of a crawl,
+// the bytes read and the time elapsed are kept with the usage of its budget.
+type checkpointStats struct {
+	StatusCodes map[int]int     `json:"statuses"`
+	Hosts       map[string]int  `json:"hosts"`
+	Latencies   []time.Duration `json:"latencies"`
+	Sampled     int             `json:"sampled"`
+}
+
+// checkpointUsage is the usage of the budgets of a crawl or a host, the time
+// already spent crawling is kept so the max duration carries on from it.
+type checkpointUsage struct {
+	Pages   int           `json:"pages"`
+	Bytes   int64         `json:"bytes"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+func newCheckpointUsage(u usage, now time.Time) checkpointUsage {
+	return checkpointUsage{Pages: u.pages, Bytes: u.bytes, Elapsed: now.Sub(u.start)}
+}
+
+func (u checkpointUsage) usage(now time.Time) *usage {
+	return &usage{pages: u.Pages, bytes: u.Bytes, start: now.Add(-u.Elapsed)}
+}
+
+type checkpointError struct {
+	URL   string `json:"url"`
+	Stage Stage  `json:"stage"`
+	Err   string `json:"err"`
+}
+
+// WithCheckpoint writes the visited URLs, the links waiting to be crawled and
+// the report of a crawl to a checkpoint in the directory every interval, when
+// the crawl is stopped and when it finishes, ResumeFrom continues the crawl
+// from the checkpoint. An interval of zero or less only writes the checkpoint
+// when the crawl stops or finishes.
+//
+// The crawl is cancelled on SIGINT or SIGTERM so the checkpoint is written
+// before the process exits. The Frontier must implement Snapshotter or Persister,
+// the links waiting in a Persister are not written to the checkpoint, it is
+// persisted instead and must be passed to the Crawler that resumes the crawl.
+func WithCheckpoint(dir string, interval time.Duration) Option {
+	return func(c *Crawler) {
+		c.checkpointDir = dir
+		c.checkpointInterval = interval
+	}
+}
+
+// ResumeFrom continues the crawl from the checkpoint in the directory written by WithCheckpoint.
+// The visited URLs are written to the Storer and the links waiting to be crawled, including
+// the links that were in flight, are pushed to the Frontier. The returned Report and error
+// include what was crawled before the checkpoint.
+//
+// If the Frontier was a Persister, the links it held are not in the checkpoint and
+// the Frontier must be a Persister that holds them, such as a Disk in the same directory.
+func (c *Crawler) ResumeFrom(ctx context.Context, dir string) (*Report, error) {
+	cp, err := readCheckpoint(dir)
+	if err != nil {
+		return nil, errors.Wrap(ErrCheckpoint, err.Error())
+	}
+
+	if _, ok := c.frontier.(Persister); cp.Persisted && !ok {
+		return nil, errors.Wrap(ErrCheckpoint, errPersisted.Error())
+	}
+
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.notify(ctx)
+	defer cancel()
+
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	r := newRun(ctx, c.newQueue(), nil)
+	c.track(r)
+
+	if err := c.restore(r, cp); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.queue.push(cp.Pending...); err != nil {
+		r.queue.stop(ErrFrontier, err)
+
+		return r.result()
+	}
+
+	return c.execute(r)
+}
+
+// notify cancels the context on SIGINT or SIGTERM when checkpoints are written.
+func (c *Crawler) notify(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.checkpointDir == "" {
+		return ctx, func() {}
+	}
+
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// restore writes the visited URLs of the checkpoint to the storer and its report to the run.
+func (c *Crawler) restore(r *run, cp *checkpoint) error {
+	visited, err := parseURLs(cp.Visited)
+	if err != nil {
+		return errors.Wrap(ErrCheckpoint, err.Error())
+	}
+
+	report, err := parseURLs(cp.Report)
+	if err != nil {
+		return errors.Wrap(ErrCheckpoint, err.Error())
+	}
+
+	errs := make(Errors, 0, len(cp.Errors))
+
+	for _, e := range cp.Errors {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			return errors.Wrap(ErrCheckpoint, err.Error())
+		}
+
+		errs = append(errs, &CrawlError{URL: u, Stage: e.Stage, Err: errors.New(e.Err)})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, u := range visited {
+		if err := c.storer.Write(u); err != nil {
+			return errors.Wrap(ErrStorer, err.Error())
+		}
+	}
+
+	now := time.Now()
+
+	hosts := make(map[string]*usage, len(cp.Hosts))
+	for host, u := range cp.Hosts {
+		hosts[host] = u.usage(now)
+	}
+
+	r.restore(&Report{Visited: report, Retried: cp.Retried}, cp.Crawled, errs, cp.Usage.usage(now), hosts)
+
+	statuses := cp.Stats.StatusCodes
+	if statuses == nil {
+		statuses = map[int]int{}
+	}
+
+	responses := cp.Stats.Hosts
+	if responses == nil {
+		responses = map[string]int{}
+	}
+
+	r.restoreStats(statuses, responses, newReservoir(cp.Stats.Latencies, cp.Stats.Sampled))
+
+	return nil
+}
+
+// checkpoint writes the state of the run to the checkpoint directory, nothing
+// is written once the run is stopped. Errors are passed to the logger.
+func (c *Crawler) checkpoint(r *run) {
+	if c.checkpointDir == "" {
+		return
+	}
+
+	r.checkpointMu.Lock()
+	defer r.checkpointMu.Unlock()
+
+	cp, ok, err := c.capture(r)
+	if err == nil && ok {
+		err = writeCheckpoint(c.checkpointDir, cp)
+	}
+
+	if err != nil {
+		c.logger.Error(errors.Wrap(ErrCheckpoint, err.Error()))
+	}
+}
+
+// capture takes the state of the run. The storer is read while holding the
+// lock used to check links, so a link is either waiting or in flight, or it
+// has been written to the storer. Links in flight are left out of the visited
+// URLs, the report, the response counts and the pages and bytes used of the
+// budgets so they are crawled again on resume. The links waiting in a Persister
+// frontier are persisted rather than captured.
+func (c *Crawler) capture(r *run) (*checkpoint, bool, error) {
+	c.mu.Lock()
+
+	pending, claimed, ok, err := r.queue.snapshot()
+	if !ok || err != nil {
+		c.mu.Unlock()
+
+		return nil, ok, err
+	}
+
+	data, err := c.storer.Read()
+	if err != nil {
+		c.mu.Unlock()
+
+		return nil, false, err
+	}
+
+	unvisited := make(map[url.URL]bool, len(claimed))
+	for _, link := range claimed {
+		unvisited[*link.URL] = true
+	}
+
+	visited := make([]string, 0, len(data))
+
+	for u, isVisited := range data {
+		if isVisited && !unvisited[u] {
+			visited = append(visited, u.String())
+		}
+	}
+
+	report, crawled, errs := r.snapshot()
+	used, hostsUsed := r.used()
+	stats, read := r.recorded(unvisited)
+
+	c.mu.Unlock()
+
+	now := time.Now()
+
+	sort.Strings(visited)
+
+	cp := &checkpoint{
+		Visited:   visited,
+		Pending:   pending,
+		Persisted: r.queue.persister != nil,
+		Report:    make([]string, 0, len(report.Visited)),
+		Retried:   report.Retried,
+		Crawled:   crawled - len(claimed),
+		Errors:    make([]checkpointError, 0, len(errs)),
+		Usage:     newCheckpointUsage(used, now),
+		Hosts:     make(map[string]checkpointUsage, len(hostsUsed)),
+		Stats:     stats,
+	}
+
+	for host, u := range hostsUsed {
+		cp.Hosts[host] = newCheckpointUsage(u, now)
+	}
+
+	for _, link := range claimed {
+		host := link.URL.Hostname()
+
+		cp.Usage.Pages--
+
+		if u, ok := cp.Hosts[host]; ok {
+			u.Pages--
+			cp.Hosts[host] = u
+		}
+	}
+
+	for host, n := range read {
+		cp.Usage.Bytes -= n
+
+		if u, ok := cp.Hosts[host]; ok {
+			u.Bytes -= n
+			cp.Hosts[host] = u
+		}
+	}
+
+	for _, u := range report.Visited {
+		if !unvisited[*u] {
+			cp.Report = append(cp.Report, u.String())
+		}
+	}
+
+	for _, e := range errs {
+		if !unvisited[*e.URL] {
+			cp.Errors = append(cp.Errors, checkpointError{URL: e.URL.String(), Stage: e.Stage, Err: e.Err.Error()})
+		}
+	}
+
+	return cp, true, nil
+}
+
+// writeCheckpoint replaces the checkpoint in the directory, it is written
+// to a temporary file first so a checkpoint is never partially written.
+func writeCheckpoint(dir string, cp *checkpoint) error {
+	if err := os.MkdirAll(dir, checkpointDirPerm); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, checkpointFile)
+
+	if err := ioutil.WriteFile(path+".tmp", b, checkpointFilePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+func readCheckpoint(dir string) (*checkpoint, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &checkpoint{}
+
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func parseURLs(rawURLs []string) ([]*url.URL, error) {
+	urls := make([]*url.URL, 0, len(rawURLs))
+
+	for _, rawURL := range rawURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,274 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/clarke94/crawler/frontier/disk"
+	"github.com/clarke94/crawler/frontier/fifo"
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCrawler_ResumeFrom(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		switch link.URL.Path {
+		case "":
+			return []*url.URL{
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			}, nil
+		case "/a":
+			return []*url.URL{testutil.URLMustParse("http://localhost/a/1")}, nil
+		default:
+			return nil, nil
+		}
+	})
+
+	tests := []struct {
+		name          string
+		givenScraper  Scraper
+		givenPolicy   FailurePolicy
+		want          *Report
+		wantErr       error
+		wantResumed   *Report
+		wantResumeErr error
+	}{
+		{
+			name: "expect crawl continued from the link that aborted the crawl",
+			givenScraper: mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				if link.URL.Path == "/a" {
+					return nil, errTest
+				}
+
+				return testScraper(link)
+			}),
+			givenPolicy: AbortOnError(),
+			want: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+			}},
+			wantErr: ErrAborted,
+			wantResumed: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/b"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/a/1"),
+			}},
+			wantResumeErr: nil,
+		},
+		{
+			name:         "expect nothing crawled on resume given a finished crawl",
+			givenScraper: testScraper,
+			givenPolicy:  ContinueOnError(),
+			want: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+				testutil.URLMustParse("http://localhost/a/1"),
+			}},
+			wantErr: nil,
+			wantResumed: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+				testutil.URLMustParse("http://localhost/a/1"),
+			}},
+			wantResumeErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(tt.givenScraper),
+				WithLogger(mockLogger{}),
+				WithFailurePolicy(tt.givenPolicy),
+				WithCheckpoint(dir, 0),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got, tt.want, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.want, ignoreStats))
+			}
+
+			resumed := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			)
+
+			got, err = resumed.ResumeFrom(context.Background(), dir)
+			if !cmp.Equal(err, tt.wantResumeErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantResumeErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got, tt.wantResumed, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.wantResumed, ignoreStats))
+			}
+		})
+	}
+}
+
+func TestCrawler_ResumeFrom_Persister(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.Depth > 0 {
+			return nil, nil
+		}
+
+		return []*url.URL{
+			testutil.URLMustParse("http://localhost/a"),
+			testutil.URLMustParse("http://localhost/b"),
+			testutil.URLMustParse("http://localhost/c"),
+		}, nil
+	})
+
+	tests := []struct {
+		name          string
+		givenFrontier bool
+		wantPending   []string
+		wantResumed   *Report
+		wantErr       error
+	}{
+		{
+			name:          "expect crawl continued from the frontier given a Disk frontier",
+			givenFrontier: true,
+			wantPending:   []string{"http://localhost/b"},
+			wantResumed: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/c"),
+				testutil.URLMustParse("http://localhost/b"),
+			}},
+			wantErr: nil,
+		},
+		{
+			name:          "expect checkpoint error given a frontier that is not a Persister",
+			givenFrontier: false,
+			wantPending:   []string{"http://localhost/b"},
+			wantResumed:   nil,
+			wantErr:       ErrCheckpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			frontierDir := filepath.Join(dir, "frontier")
+
+			frontier, err := disk.New(frontierDir, disk.WithSegmentSize(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+				WithFrontier(frontier),
+				WithMaxPages(2),
+				WithCheckpoint(dir, 0),
+			)
+
+			_, err = c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, ErrBudget, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrBudget, cmpopts.EquateErrors()))
+			}
+
+			cp, err := readCheckpoint(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pending := make([]string, 0, len(cp.Pending))
+			for _, link := range cp.Pending {
+				pending = append(pending, link.URL.String())
+			}
+
+			if !cmp.Equal(pending, tt.wantPending) {
+				t.Error(cmp.Diff(pending, tt.wantPending))
+			}
+
+			options := []Option{
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			}
+
+			if tt.givenFrontier {
+				reopened, err := disk.New(frontierDir, disk.WithSegmentSize(1))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				options = append(options, WithFrontier(reopened))
+			}
+
+			got, err := New(options...).ResumeFrom(context.Background(), dir)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got, tt.wantResumed, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.wantResumed, ignoreStats))
+			}
+		})
+	}
+}
+
+func TestCrawler_ResumeFrom_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenFrontier Frontier
+		wantErr       error
+	}{
+		{
+			name:          "expect checkpoint error given no checkpoint in the directory",
+			givenFrontier: nil,
+			wantErr:       ErrCheckpoint,
+		},
+		{
+			name:          "expect checkpoint error given a frontier that is not a Snapshotter",
+			givenFrontier: &mockFrontier{Frontier: fifo.New()},
+			wantErr:       ErrCheckpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if tt.givenFrontier != nil {
+				c := New(
+					WithFrontier(tt.givenFrontier),
+					WithCheckpoint(dir, 0),
+				)
+
+				_, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+				if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+					t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+				}
+			}
+
+			_, err := New().ResumeFrom(context.Background(), dir)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
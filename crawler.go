@@ -5,10 +5,15 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/clarke94/crawler/enforce/samedomainonce"
+	"github.com/clarke94/crawler/frontier/fifo"
+	"github.com/clarke94/crawler/limit/perhost"
 	print2 "github.com/clarke94/crawler/log/print"
+	"github.com/clarke94/crawler/page"
 	"github.com/clarke94/crawler/request/get"
 	"github.com/clarke94/crawler/scrape/html"
 	"github.com/clarke94/crawler/storage/memory"
@@ -28,6 +33,22 @@ var (
 	// ErrStorer is the annotated error that is wrapped with
 	// the returned error from the Storer.
 	ErrStorer = errors.New("storage error")
+	// ErrFrontier is the annotated error that is wrapped with
+	// the returned error from the Frontier.
+	ErrFrontier = errors.New("frontier error")
+	// ErrAborted is the reason given when a crawl is stopped by the FailurePolicy.
+	ErrAborted = errors.New("crawl aborted")
+	// ErrCanceled is the reason given when a crawl is stopped by its context.
+	ErrCanceled = errors.New("crawl canceled")
+	// ErrCircuitOpen is the error for a URL that is not requested because
+	// the circuit breaker of its host is open.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+	// ErrLogin is the annotated error that is wrapped with
+	// the returned error from the login hook.
+	ErrLogin = errors.New("login error")
+	// ErrBudget is the reason given when a crawl is stopped by its max pages,
+	// max bytes or max duration.
+	ErrBudget = errors.New("crawl budget exhausted")
 )
 
 // Storer provides an interface to the storage layer.
@@ -38,58 +59,133 @@ type Storer interface {
 
 // Scraper provides an interface to extract data and return urls.
 type Scraper interface {
-	Scrape(req *http.Request, closer io.ReadCloser) ([]*url.URL, error)
+	Scrape(link *page.Link, resp *page.Response) ([]*url.URL, error)
 }
 
-// Requester provides the interface for a HTTP Request.
+// Requester provides the interface for a HTTP Request. The context of a request
+// carries the scope of its link, read with page.ScopeFromContext.
 type Requester interface {
 	Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error)
-	Do(req *http.Request) (io.ReadCloser, error)
+	Do(req *http.Request) (*page.Response, error)
 }
 
 // Logger provides the interface to log output from the Crawler.
 type Logger interface {
 	Error(err error)
-	Info(visited *url.URL, found []*url.URL)
+	Info(visited *page.Link, resp *page.Response, found []*url.URL)
+}
+
+// Frontier provides an interface to order the links waiting to be crawled.
+// Only one goroutine uses the Frontier at a time.
+type Frontier interface {
+	Push(link *page.Link) error
+	Pop() (*page.Link, error)
+	Len() int
+}
+
+// Persister is implemented by a Frontier that keeps its links between crawls.
+// It is not emptied when a crawl stops early, the links that were deferred or
+// not crawled are pushed back to it and Persist is called once the crawl ends.
+type Persister interface {
+	Persist() error
+}
+
+// Resetter is implemented by a Storer that can clear its visited URLs.
+type Resetter interface {
+	Reset() error
+}
+
+// Limiter provides an interface to throttle the requests sent to a host.
+type Limiter interface {
+	Wait(ctx context.Context, u *url.URL) error
+}
+
+// Scheduler is implemented by a Limiter that can report the time a request can be
+// sent to the host of a URL without waiting, the zero time if it can be sent now.
+// The links of a host are deferred until it is ready and only one link for a host
+// waits for the Limiter at a time, so the workers crawl other hosts rather than
+// waiting on a slow one.
+type Scheduler interface {
+	Ready(u *url.URL) time.Time
 }
 
 // Enforcer provides an interface to enforce logic before scraping.
 type Enforcer interface {
-	Enforce(data map[url.URL]bool, url *url.URL) bool
+	Enforce(data map[url.URL]bool, link *page.Link) bool
 }
 
+// defaultConcurrency is the number of workers used when WithConcurrency is not provided.
+const defaultConcurrency = 10
+
+// noMaxDepth is the max depth used when WithMaxDepth is not provided.
+const noMaxDepth = -1
+
 // Option is a functional option to modify the default Crawler instance.
 type Option func(crawler *Crawler)
 
+// Report is the summary of a crawl.
+type Report struct {
+	// Visited is every URL that received a response, in the order they were crawled.
+	Visited []*url.URL
+	// Retried is the number of requests sent again by the RetryPolicy.
+	Retried int
+	// Stats is the summary of the crawl.
+	Stats Stats
+}
+
 // Crawler provides a web crawler.
 type Crawler struct {
-	Context context.Context
-
 	requester Requester
+	limiter   Limiter
 	enforcer  Enforcer
 	scraper   Scraper
 	storer    Storer
 	logger    Logger
-	wg        *sync.WaitGroup
-	mu        *sync.RWMutex
-	errMu     *sync.RWMutex
-	err       error
+	policy    FailurePolicy
+	retry     RetryPolicy
+	frontier  Frontier
+	login     func(ctx context.Context) error
+
+	concurrency int
+	maxDepth    int
+	maxPerHost  int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	budget      budget
+	hostBudget  budget
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	breakerMode      BreakerMode
+	sharedStorer     bool
+	mu               *sync.RWMutex
+	gate             *gate
+	runs             int
+	current          *run
+
+	checkpointDir      string
+	checkpointInterval time.Duration
 }
 
 // New initializes a new default Crawler.
 func New(options ...Option) *Crawler {
 	c := &Crawler{
-		Context: context.Background(),
-
 		requester: get.New(),
+		limiter:   perhost.New(),
 		enforcer:  samedomainonce.New(),
 		scraper:   html.New(),
 		storer:    memory.New(),
 		logger:    &print2.Print{},
-		wg:        &sync.WaitGroup{},
-		mu:        &sync.RWMutex{},
-		errMu:     &sync.RWMutex{},
-		err:       nil,
+		policy:    ContinueOnError(),
+		retry:     NoRetry(),
+		frontier:  fifo.New(),
+
+		concurrency: defaultConcurrency,
+		maxDepth:    noMaxDepth,
+		minBackoff:  defaultMinBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		mu:          &sync.RWMutex{},
+		gate:        newGate(),
 	}
 
 	for _, opt := range options {
@@ -99,6 +195,49 @@ func New(options ...Option) *Crawler {
 	return c
 }
 
+// WithBackoff sets the first and longest delay between the requests to a host that responds
+// with 429 Too Many Requests or 503 Service Unavailable. The delay is doubled on each of these
+// responses and halved again as the host recovers, a Retry-After header is honoured up to max.
+// A min of zero or less does not throttle hosts.
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Crawler) {
+		if max < min {
+			max = min
+		}
+
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// WithCircuitBreaker opens the circuit breaker of a host after threshold consecutive requests
+// to it fail with an error or a 5xx response. The URLs of an open host are deferred or failed
+// with ErrCircuitOpen depending on the mode, once the cooldown is over a single request is sent
+// to probe the host and it is closed again if the probe succeeds. Values less than one are ignored.
+func WithCircuitBreaker(threshold int, cooldown time.Duration, mode BreakerMode) Option {
+	return func(c *Crawler) {
+		if threshold < 1 {
+			return
+		}
+
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+		c.breakerMode = mode
+	}
+}
+
+// WithConcurrency sets the maximum number of URLs crawled at the same time.
+// Values less than one are ignored.
+func WithConcurrency(n int) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.concurrency = n
+	}
+}
+
 // WithEnforcer replaces the default enforcer with the provided one.
 func WithEnforcer(enforcer Enforcer) Option {
 	return func(c *Crawler) {
@@ -106,6 +245,31 @@ func WithEnforcer(enforcer Enforcer) Option {
 	}
 }
 
+// WithFailurePolicy replaces the default ContinueOnError policy with the provided one.
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(c *Crawler) {
+		c.policy = policy
+	}
+}
+
+// WithFrontier replaces the default breadth first FIFO frontier with the provided one.
+// The frontier is emptied when a crawl is stopped early so the next crawl starts afresh,
+// unless it implements Persister.
+func WithFrontier(frontier Frontier) Option {
+	return func(c *Crawler) {
+		c.frontier = frontier
+	}
+}
+
+// WithLimiter replaces the default limiter, which does not throttle requests, with the provided one.
+// A Limiter that is not a Scheduler blocks a worker while it waits, use WithMaxPerHost so the
+// workers do not all wait on a single host.
+func WithLimiter(limiter Limiter) Option {
+	return func(c *Crawler) {
+		c.limiter = limiter
+	}
+}
+
 // WithLogger replaces the default logger with the provided one.
 func WithLogger(logger Logger) Option {
 	return func(c *Crawler) {
@@ -113,6 +277,110 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithMaxDepth sets the maximum number of links followed from the seed URL,
+// found URLs beyond the max depth are not crawled. Values less than zero are ignored.
+func WithMaxDepth(n int) Option {
+	return func(c *Crawler) {
+		if n < 0 {
+			return
+		}
+
+		c.maxDepth = n
+	}
+}
+
+// WithLogin sets a hook called before the seeds of each crawl are fetched, such as to submit a login
+// form with a Requester that keeps cookies. The crawl is not started if it returns an error.
+func WithLogin(login func(ctx context.Context) error) Option {
+	return func(c *Crawler) {
+		c.login = login
+	}
+}
+
+// WithMaxBytes sets the maximum number of bytes read from response bodies, the crawl is
+// stopped with ErrBudget once it is reached and a URL is left. Values less than one are ignored.
+func WithMaxBytes(n int64) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.budget.bytes = n
+	}
+}
+
+// WithMaxBytesPerHost sets the maximum number of bytes read from the response bodies
+// of a single host, the URLs of a host that reached it are skipped. Values less than one are ignored.
+func WithMaxBytesPerHost(n int64) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.hostBudget.bytes = n
+	}
+}
+
+// WithMaxDuration sets the maximum time a crawl runs for,
+// the crawl is stopped with ErrBudget once it is over. Values less than one are ignored.
+func WithMaxDuration(d time.Duration) Option {
+	return func(c *Crawler) {
+		if d < 1 {
+			return
+		}
+
+		c.budget.duration = d
+	}
+}
+
+// WithMaxDurationPerHost sets the maximum time a single host is crawled for from its first request,
+// the URLs of a host are skipped once it is over. Values less than one are ignored.
+func WithMaxDurationPerHost(d time.Duration) Option {
+	return func(c *Crawler) {
+		if d < 1 {
+			return
+		}
+
+		c.hostBudget.duration = d
+	}
+}
+
+// WithMaxPages sets the maximum number of requests sent, including retries, the crawl is
+// stopped with ErrBudget once it is reached and a URL is left. Values less than one are ignored.
+func WithMaxPages(n int) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.budget.pages = n
+	}
+}
+
+// WithMaxPagesPerHost sets the maximum number of requests sent to a single host, including
+// retries, the URLs of a host that reached it are skipped. Values less than one are ignored.
+func WithMaxPagesPerHost(n int) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.hostBudget.pages = n
+	}
+}
+
+// WithMaxPerHost sets the maximum number of URLs of a single host crawled at the same time,
+// URLs of other hosts are crawled while a host is at its maximum. Values less than one are ignored.
+func WithMaxPerHost(n int) Option {
+	return func(c *Crawler) {
+		if n < 1 {
+			return
+		}
+
+		c.maxPerHost = n
+	}
+}
+
 // WithRequester replaces the default requester with the provided one.
 func WithRequester(requester Requester) Option {
 	return func(c *Crawler) {
@@ -120,6 +388,14 @@ func WithRequester(requester Requester) Option {
 	}
 }
 
+// WithRetryPolicy replaces the default NoRetry policy with the provided one. A retried URL is
+// queued again once its wait is over and the RetryError is passed to the logger.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Crawler) {
+		c.retry = policy
+	}
+}
+
 // WithScraper replaces the default scraper with the provided one.
 func WithScraper(scraper Scraper) Option {
 	return func(c *Crawler) {
@@ -127,6 +403,14 @@ func WithScraper(scraper Scraper) Option {
 	}
 }
 
+// WithSharedStorer shares the visited URLs in the storer between calls to Crawl,
+// by default a Storer that implements Resetter is reset between calls.
+func WithSharedStorer(shared bool) Option {
+	return func(c *Crawler) {
+		c.sharedStorer = shared
+	}
+}
+
 // WithStorer replaces the default storer with the provided one.
 func WithStorer(storer Storer) Option {
 	return func(c *Crawler) {
@@ -134,91 +418,507 @@ func WithStorer(storer Storer) Option {
 	}
 }
 
-// Crawl sends a request to a given URL and scrapes data from the response.
-func (c *Crawler) Crawl(u *url.URL) error {
-	if u == nil {
-		return ErrInvalidURL
+// Crawl sends a request to the given seed URLs and scrapes data from the responses.
+// It is a shorthand for CrawlContext with a background context.
+func (c *Crawler) Crawl(seeds ...*url.URL) error {
+	_, err := c.CrawlContext(context.Background(), seeds...)
+
+	return err
+}
+
+// CrawlContext sends a request to the given seed URLs and scrapes data from the responses.
+// Found URLs are queued and crawled by a fixed pool of workers.
+// The seeds may be on different hosts, the hosts of the seeds are the
+// page.Scope given to the Enforcer with every link.
+//
+// Cancelling the context stops new URLs being crawled and aborts the requests
+// in flight, the returned Report holds what was crawled so far and the error
+// is a StopError with the reason ErrCanceled.
+//
+// Otherwise the returned error is an Errors collection of every URL that failed,
+// or a StopError if the FailurePolicy aborted the crawl or a budget was exhausted.
+//
+// Each call has its own errors and report, the visited URLs in the Storer
+// are reset between calls unless WithSharedStorer is provided.
+func (c *Crawler) CrawlContext(ctx context.Context, seeds ...*url.URL) (*Report, error) {
+	return c.crawlSeeds(ctx, seeds, nil)
+}
+
+// crawlSeeds crawls the seeds with a new run, sending the result of
+// each crawled URL to the results channel if it is not nil.
+func (c *Crawler) crawlSeeds(ctx context.Context, seeds []*url.URL, results chan<- Result) (*Report, error) {
+	if len(seeds) == 0 {
+		return nil, ErrInvalidURL
+	}
+
+	for _, u := range seeds {
+		if u == nil {
+			return nil, ErrInvalidURL
+		}
+	}
+
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.notify(ctx)
+	defer cancel()
+
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	r := newRun(ctx, c.newQueue(), results)
+	c.track(r)
+
+	if err := r.seed(seeds); err != nil {
+		r.queue.stop(ErrFrontier, err)
+
+		return r.result()
+	}
+
+	return c.execute(r)
+}
+
+// newQueue initializes a new queue for a run from the frontier and the limits of the crawler.
+func (c *Crawler) newQueue() *queue {
+	return newQueue(
+		c.frontier,
+		c.limiter,
+		c.maxPerHost,
+		newThrottle(c.minBackoff, c.maxBackoff),
+		newBreaker(c.breakerThreshold, c.breakerCooldown, c.breakerMode),
+	)
+}
+
+// prepare resets the storer and checks the frontier can be checkpointed.
+func (c *Crawler) prepare() error {
+	_, snapshots := c.frontier.(Snapshotter)
+	_, persists := c.frontier.(Persister)
+
+	if c.checkpointDir != "" && !snapshots && !persists {
+		return errors.Wrap(ErrCheckpoint, errNoSnapshot.Error())
 	}
 
-	c.goCrawl(u)
-	c.wg.Wait()
+	return c.reset()
+}
 
-	c.errMu.RLock()
-	defer c.errMu.RUnlock()
+// authenticate calls the login hook, if there is one.
+func (c *Crawler) authenticate(ctx context.Context) error {
+	if c.login == nil {
+		return nil
+	}
+
+	if err := c.login(ctx); err != nil {
+		return errors.Wrap(ErrLogin, err.Error())
+	}
 
-	return c.err
+	return nil
 }
 
-// goCrawl is a concurrent wrapper around the crawl method.
-func (c *Crawler) goCrawl(u *url.URL) {
-	c.wg.Add(1)
+// execute crawls the links in the queue of the run with a pool of workers
+// until the queue is exhausted or stopped.
+func (c *Crawler) execute(r *run) (*Report, error) {
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+
+		go c.work(r, wg)
+	}
 
-	go func(c *Crawler) {
-		if err := c.crawl(u); err != nil {
-			c.errMu.Lock()
-			defer c.errMu.Unlock()
-			c.err = err
+	finished := make(chan struct{})
+	watched := make(chan struct{})
+
+	go func() {
+		c.watch(r, finished)
+		close(watched)
+	}()
+
+	wg.Wait()
+	close(finished)
+	<-watched
+
+	c.checkpoint(r)
+
+	if err := r.queue.persist(); err != nil {
+		c.logger.Error(errors.Wrap(ErrFrontier, err.Error()))
+		r.queue.stop(ErrFrontier, err)
+	}
+
+	return r.result()
+}
+
+// watch stops the run when its context is done or its max duration is over and
+// writes a checkpoint every checkpoint interval, until the run is finished.
+func (c *Crawler) watch(r *run, finished <-chan struct{}) {
+	var tick, deadline <-chan time.Time
+
+	if c.budget.duration > 0 {
+		timer := time.NewTimer(r.remaining(c.budget))
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	if c.checkpointDir != "" && c.checkpointInterval > 0 {
+		ticker := time.NewTicker(c.checkpointInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			c.stop(r, ErrCanceled, r.ctx.Err())
+
+			return
+		case <-deadline:
+			c.stop(r, ErrBudget, r.exhausted(c.budget))
+
+			return
+		case <-tick:
+			c.checkpoint(r)
+		case <-finished:
+			return
 		}
-	}(c)
+	}
 }
 
-// crawl checks the URL with the enforcer to see if the conditions are met
-// and then invokes the requester to create and send the request.
-// The request is stored in the storer and the response is passed
-// to the scraper to extract the data and return found URLs, the
-// request is passed to the logger and any found urls are recursively crawled.
-func (c *Crawler) crawl(u *url.URL) error {
-	defer c.wg.Done()
+// stop writes a checkpoint of the run before stopping its queue,
+// so the links discarded by the queue are kept in the checkpoint.
+func (c *Crawler) stop(r *run, reason, cause error) {
+	c.checkpoint(r)
+	r.queue.stop(reason, cause)
+}
+
+// reset clears the visited URLs of the storer left from a previous crawl,
+// the storer is left as is for the first crawl or if it is shared.
+func (c *Crawler) reset() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.runs++
+	if c.runs == 1 || c.sharedStorer {
+		return nil
+	}
+
+	resetter, ok := c.storer.(Resetter)
+	if !ok {
+		return nil
+	}
+
+	if err := resetter.Reset(); err != nil {
+		return errors.Wrap(ErrStorer, err.Error())
+	}
+
+	return nil
+}
+
+// work crawls links from the queue until the queue is exhausted or stopped,
+// a popped link is held while the Crawler is paused. A link whose request
+// fails because the context is cancelled, or that the budgets of the crawl
+// refuse, is requeued and the crawl is stopped.
+func (c *Crawler) work(r *run, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		link, ok := r.queue.pop()
+		if !ok {
+			return
+		}
+
+		c.gate.wait(r.ctx)
+
+		if r.ctx.Err() != nil {
+			c.stop(r, ErrCanceled, r.ctx.Err())
+			r.queue.requeue(link)
+
+			continue
+		}
+
+		resp, urls, err := c.crawl(r, link)
+		last := r.attempted(link)
+
+		switch {
+		case err != nil && resp == nil && r.ctx.Err() != nil:
+			c.stop(r, ErrCanceled, r.ctx.Err())
+			r.queue.requeue(link)
+
+			continue
+		case resp == nil && err == nil && last != nil:
+			// A retry the budgets refuse fails with the error of its last attempt.
+			err = last
+		case resp == nil && err == nil && r.refusal() != nil:
+			c.stop(r, ErrBudget, r.refusal())
+			r.queue.requeue(link)
+
+			continue
+		}
+
+		var retryErr *RetryError
+		if err != nil && err != last && r.ctx.Err() == nil && errors.As(err.Err, &retryErr) {
+			c.logger.Error(retryErr)
+
+			retry := link.Retry()
+			r.retry(retry, err)
+			r.queue.retry(link, retry, time.Now().Add(retryErr.Wait))
+
+			continue
+		}
+
+		switch {
+		case err != nil && r.ctx.Err() != nil:
+			c.stop(r, ErrCanceled, r.ctx.Err())
+		case err != nil && c.fail(r, err):
+			c.stop(r, ErrAborted, nil)
+		}
+
+		if budgetErr := r.refusal(); budgetErr != nil {
+			c.stop(r, ErrBudget, budgetErr)
+		}
+
+		if resp != nil || err != nil {
+			r.emit(newResult(link, resp, urls, err))
+		}
+
+		failed, pushErr := r.queue.done(link, c.follow(link, urls)...)
+		if pushErr != nil && c.fail(r, &CrawlError{URL: failed.URL, Stage: StageFrontier, Err: pushErr}) {
+			c.stop(r, ErrAborted, nil)
+		}
+	}
+}
+
+// fail records the error for the crawl and passes it to the logger,
+// it reports whether the FailurePolicy aborts the crawl.
+func (c *Crawler) fail(r *run, err *CrawlError) bool {
+	abort := r.fail(err, c.policy)
+
+	c.logger.Error(err)
+
+	return abort
+}
+
+// follow returns the links for the URLs found on the page of the given link
+// that are within the max depth and have not been visited.
+func (c *Crawler) follow(link *page.Link, urls []*url.URL) []*page.Link {
+	if c.maxDepth != noMaxDepth && link.Depth >= c.maxDepth {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A storer error is reported when the link is checked before it is crawled.
+	visited, _ := c.storer.Read()
+
+	links := make([]*page.Link, 0, len(urls))
 
+	for _, u := range urls {
+		if visited[*u] {
+			continue
+		}
+
+		links = append(links, link.Child(u))
+	}
+
+	return links
+}
+
+// crawl checks the link with the enforcer to see if the conditions are met,
+// waits for the limiter and then invokes the requester to create and send the
+// request with the scope of the link in its context. A failed request the
+// RetryPolicy retries is returned as a RetryError. The request is stored in
+// the storer and a 2xx response is passed to the scraper to extract the data
+// and return found URLs, other responses and redirects the enforcer does not
+// allow are not scraped. The response is passed to the logger and any found
+// urls are returned to be queued. The response and error are both nil if the
+// link is not crawled.
+func (c *Crawler) crawl(r *run, link *page.Link) (*page.Response, []*url.URL, *CrawlError) {
 	c.mu.Lock()
-	ok, err := c.check(u)
+	ok, err := c.admit(r, link)
 	c.mu.Unlock()
 
 	if err != nil {
-		return err
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageStorer, Err: err}
 	}
 
 	if !ok {
-		return nil
+		return nil, nil, nil
+	}
+
+	if !r.queue.acquire(link) {
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageRequester, Err: ErrCircuitOpen}
 	}
 
-	req, err := c.requester.Request(c.Context, u.String(), nil)
+	err = c.limiter.Wait(r.ctx, link.URL)
+	r.queue.waited(link)
+
 	if err != nil {
-		return errors.Wrap(ErrRequester, err.Error())
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageRequester, Err: err}
 	}
 
+	req, err := c.requester.Request(page.ContextWithScope(r.ctx, link.Scope), link.URL.String(), nil)
+	if err != nil {
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageRequester, Err: err}
+	}
+
+	read := new(int64)
+
 	resp, err := c.requester.Do(req)
+	if resp != nil && resp.Body != nil {
+		resp.Body = &meter{ReadCloser: resp.Body, run: r, host: link.URL.Hostname(), read: read}
+	}
+
+	if r.ctx.Err() == nil {
+		r.queue.observe(link, resp, err)
+	}
+
+	if wait, ok := c.retry.Retry(link.Attempt+1, resp, err); ok && r.ctx.Err() == nil {
+		retryErr := &RetryError{URL: link.URL, Attempt: link.Attempt + 1, Wait: wait, Err: err}
+
+		if resp != nil {
+			retryErr.StatusCode = resp.StatusCode
+			discard(resp.Body)
+		}
+
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageRequester, Err: retryErr}
+	}
+
 	if err != nil {
-		return errors.Wrap(ErrRequester, err.Error())
+		return nil, nil, &CrawlError{URL: link.URL, Stage: StageRequester, Err: err}
+	}
+
+	r.visit(link.URL, resp, read)
+
+	allowed, redirectErr := c.redirect(link, resp)
+	if redirectErr != nil {
+		discard(resp.Body)
+
+		return resp, nil, &CrawlError{URL: link.URL, Stage: StageStorer, Err: redirectErr}
 	}
 
-	urls, err := c.scraper.Scrape(req, resp)
+	if !allowed || !resp.OK() {
+		discard(resp.Body)
+		c.logger.Info(link, resp, nil)
+
+		return resp, nil, nil
+	}
+
+	urls, err := c.scraper.Scrape(link, resp)
 	if err != nil {
-		return errors.Wrap(ErrScraper, err.Error())
+		return resp, nil, &CrawlError{URL: link.URL, Stage: StageScraper, Err: err}
 	}
 
-	c.logger.Info(req.URL, urls)
+	c.logger.Info(link, resp, urls)
+
+	return resp, urls, nil
+}
+
+// admit checks a link with the storer and enforcer, a retried link was checked
+// on its first attempt and is only checked against the budgets. An admitted link
+// is claimed and counted as crawled.
+func (c *Crawler) admit(r *run, link *page.Link) (bool, error) {
+	if link.Attempt > 0 {
+		if !r.reserve(link.URL.Hostname(), c.budget, c.hostBudget) {
+			return false, nil
+		}
+
+		r.queue.claim(link)
 
-	for _, foundURL := range urls {
-		c.goCrawl(foundURL)
+		return true, nil
 	}
 
-	return nil
+	ok, err := c.check(r, link)
+	if ok {
+		r.queue.claim(link)
+	}
+
+	if ok || err != nil {
+		r.count()
+	}
+
+	return ok, err
 }
 
-func (c *Crawler) check(u *url.URL) (bool, error) {
+// check reports whether a link has not been visited, is allowed by the enforcer and is within
+// the budgets, it is written to the storer if it is.
+func (c *Crawler) check(r *run, link *page.Link) (bool, error) {
 	visitedURLs, err := c.storer.Read()
 	if err != nil {
-		return false, errors.Wrap(ErrStorer, err.Error())
+		return false, err
+	}
+
+	if ok := c.enforcer.Enforce(visitedURLs, link); !ok {
+		return false, nil
+	}
+
+	if ok := r.reserve(link.URL.Hostname(), c.budget, c.hostBudget); !ok {
+		return false, nil
+	}
+
+	if writeErr := c.storer.Write(link.URL); writeErr != nil {
+		return false, writeErr
+	}
+
+	return true, nil
+}
+
+// redirect checks the URL a link was redirected to with the enforcer, so a redirect to another
+// host or a visited URL is not scraped, and writes the URLs redirected through to the storer so
+// they are not crawled again. A redirect to the same page, such as from http to https or to add a
+// trailing slash, is not checked.
+func (c *Crawler) redirect(link *page.Link, resp *page.Response) (bool, error) {
+	if len(resp.Redirects) == 0 {
+		return true, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	visited, err := c.storer.Read()
+	if err != nil {
+		return false, err
 	}
 
-	if ok := c.enforcer.Enforce(visitedURLs, u); !ok {
+	target := *link
+	target.URL = resp.URL
+
+	if !samePage(link.URL, resp.URL) && !c.enforcer.Enforce(visited, &target) {
 		return false, nil
 	}
 
-	if writeErr := c.storer.Write(u); writeErr != nil {
-		return false, errors.Wrap(ErrStorer, writeErr.Error())
+	urls := make([]*url.URL, 0, len(resp.Redirects))
+	urls = append(urls, resp.Redirects[1:]...)
+	urls = append(urls, resp.URL)
+
+	for _, u := range urls {
+		if visited[*u] {
+			continue
+		}
+
+		if err := c.storer.Write(u); err != nil {
+			return false, err
+		}
 	}
 
 	return true, nil
 }
+
+// samePage reports whether the URLs are for the same page ignoring the scheme and a trailing slash.
+func samePage(a, b *url.URL) bool {
+	return a.Hostname() == b.Hostname() &&
+		strings.TrimSuffix(a.Path, "/") == strings.TrimSuffix(b.Path, "/") &&
+		a.RawQuery == b.RawQuery
+}
+
+// discard reads and closes a response body so the connection can be reused.
+func discard(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, body)
+	_ = body.Close()
+}
@@ -2,15 +2,30 @@ package crawler
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/clarke94/crawler/enforce/samedomainonce"
+	"github.com/clarke94/crawler/frontier/disk"
+	"github.com/clarke94/crawler/frontier/fifo"
+	"github.com/clarke94/crawler/frontier/lifo"
+	"github.com/clarke94/crawler/frontier/priority"
 	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/limit/perhost"
+	"github.com/clarke94/crawler/page"
+	"github.com/clarke94/crawler/request/get"
+	"github.com/clarke94/crawler/request/method"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 )
 
 func TestNew(t *testing.T) {
@@ -20,9 +35,7 @@ func TestNew(t *testing.T) {
 	}{
 		{
 			name: "expect crawler to initialize",
-			want: &Crawler{
-				Context: context.Background(),
-			},
+			want: &Crawler{},
 		},
 	}
 	for _, tt := range tests {
@@ -342,13 +355,18 @@ func TestCrawler_Crawl_Fail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := Crawler{
 				requester: tt.givenRequester,
+				limiter:   perhost.New(),
 				scraper:   tt.givenScraper,
 				storer:    tt.givenStorer,
 				logger:    tt.givenLogger,
 				enforcer:  tt.givenEnforcer,
-				wg:        &sync.WaitGroup{},
-				mu:        &sync.RWMutex{},
-				errMu:     &sync.RWMutex{},
+				policy:    ContinueOnError(),
+				retry:     NoRetry(),
+				frontier:  fifo.New(),
+
+				concurrency: 1,
+				mu:          &sync.RWMutex{},
+				gate:        newGate(),
 			}
 
 			err := c.Crawl(tt.givenURL)
@@ -359,180 +377,1531 @@ func TestCrawler_Crawl_Fail(t *testing.T) {
 	}
 }
 
-func TestCrawler_WithEnforcer(t *testing.T) {
+func TestCrawler_CrawlContext(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
 	tests := []struct {
-		name          string
-		givenEnforcer Enforcer
-		want          *Crawler
+		name        string
+		givenSeeds  []*url.URL
+		givenCancel bool
+		want        *Report
+		wantErr     error
 	}{
 		{
-			name:          "expect custom enforcer initialize",
-			givenEnforcer: mockEnforcer{},
-			want: &Crawler{
-				enforcer: mockEnforcer{},
+			name:       "expect every seed visited",
+			givenSeeds: []*url.URL{testutil.URLMustParse("http://localhost"), testutil.URLMustParse("http://localhost/foo")},
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://localhost"),
+					testutil.URLMustParse("http://localhost/foo"),
+					testutil.URLMustParse("http://localhost/bar"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "expect crawled so far and canceled error given the context is canceled",
+			givenSeeds:  []*url.URL{testutil.URLMustParse("http://localhost")},
+			givenCancel: true,
+			want: &Report{
+				Visited: []*url.URL{
+					testutil.URLMustParse("http://localhost"),
+				},
 			},
+			wantErr: ErrCanceled,
+		},
+		{
+			name:       "expect error given no seeds",
+			givenSeeds: nil,
+			want:       nil,
+			wantErr:    ErrInvalidURL,
+		},
+		{
+			name:       "expect error given a nil seed",
+			givenSeeds: []*url.URL{testutil.URLMustParse("http://localhost"), nil},
+			want:       nil,
+			wantErr:    ErrInvalidURL,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := New(WithEnforcer(tt.givenEnforcer))
-			if !cmp.Equal(got.enforcer, tt.want.enforcer) {
-				t.Error(cmp.Diff(got.enforcer, tt.want.enforcer))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			scraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				if tt.givenCancel {
+					cancel()
+				}
+
+				if link.Depth > 0 {
+					return nil, nil
+				}
+
+				return []*url.URL{testutil.URLMustParse("http://localhost/bar")}, nil
+			})
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+			)
+
+			got, err := c.CrawlContext(ctx, tt.givenSeeds...)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if tt.givenCancel && !errors.Is(err, context.Canceled) {
+				t.Errorf("expected %v to wrap %v", err, context.Canceled)
+			}
+
+			if !cmp.Equal(got, tt.want, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.want, ignoreStats))
 			}
 		})
 	}
 }
 
-func TestCrawler_Withlogger(t *testing.T) {
+func TestCrawler_CrawlContext_Seeds(t *testing.T) {
+	handlers := []testutil.Handler{
+		{
+			Pattern: "/",
+			HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+				rw.Header().Set("Content-Type", "text/html")
+				_, _ = rw.Write([]byte(`
+					<a href="http://localhost:8080/foo">Other seed host</a>
+					<a href="https://example.com">Out of scope</a>
+				`))
+			},
+		},
+	}
+
 	tests := []struct {
-		name        string
-		givenLogger Logger
-		want        *Crawler
+		name       string
+		givenSeeds []*url.URL
+		want       []*url.URL
 	}{
 		{
-			name:        "expect custom logger initialize",
-			givenLogger: mockLogger{},
-			want: &Crawler{
-				logger: mockLogger{},
+			name:       "expect only the seed host given a single seed",
+			givenSeeds: []*url.URL{testutil.URLMustParse("http://127.0.0.1:8080/")},
+			want: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/"),
+			},
+		},
+		{
+			name: "expect links on every seed host given seeds on different hosts",
+			givenSeeds: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/"),
+				testutil.URLMustParse("http://localhost:8080/"),
+			},
+			want: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/"),
+				testutil.URLMustParse("http://localhost:8080/"),
+				testutil.URLMustParse("http://localhost:8080/foo"),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := New(WithLogger(tt.givenLogger))
-			if !cmp.Equal(got.logger, tt.want.logger) {
-				t.Error(cmp.Diff(got.logger, tt.want.logger))
+			ts := testutil.NewTestServer(handlers...)
+			defer ts.Close()
+
+			c := New(WithConcurrency(1), WithLogger(mockLogger{}))
+
+			got, err := c.CrawlContext(context.Background(), tt.givenSeeds...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(got.Visited, tt.want) {
+				t.Error(cmp.Diff(got.Visited, tt.want))
 			}
 		})
 	}
 }
 
-func TestCrawler_WithRequester(t *testing.T) {
+func TestCrawler_CrawlContext_Reuse(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
 	tests := []struct {
-		name           string
-		givenRequester Requester
-		want           *Crawler
+		name        string
+		givenShared bool
+		want        []*Report
+		wantErr     []error
 	}{
 		{
-			name:           "expect custom Requester initialize",
-			givenRequester: mockRequester{},
-			want: &Crawler{
-				requester: mockRequester{},
+			name:        "expect each crawl to have its own errors and visited URLs",
+			givenShared: false,
+			want: []*Report{
+				{Visited: []*url.URL{testutil.URLMustParse("http://localhost")}},
+				{Visited: []*url.URL{testutil.URLMustParse("http://localhost")}},
+			},
+			wantErr: []error{ErrScraper, nil},
+		},
+		{
+			name:        "expect visited URLs shared between crawls given a shared storer",
+			givenShared: true,
+			want: []*Report{
+				{Visited: []*url.URL{testutil.URLMustParse("http://localhost")}},
+				{Visited: nil},
 			},
+			wantErr: []error{ErrScraper, nil},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := New(WithRequester(tt.givenRequester))
-			if !cmp.Equal(got.requester, tt.want.requester) {
-				t.Error(cmp.Diff(got.requester, tt.want.requester))
+			calls := 0
+			scraper := mockScraperFunc(func(_ *page.Link) ([]*url.URL, error) {
+				calls++
+				if calls == 1 {
+					return nil, errTest
+				}
+
+				return nil, nil
+			})
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+				WithSharedStorer(tt.givenShared),
+			)
+
+			for i := range tt.want {
+				got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+				if !cmp.Equal(err, tt.wantErr[i], cmpopts.EquateErrors()) {
+					t.Error(cmp.Diff(err, tt.wantErr[i], cmpopts.EquateErrors()))
+				}
+
+				if !cmp.Equal(got, tt.want[i], ignoreStats) {
+					t.Error(cmp.Diff(got, tt.want[i], ignoreStats))
+				}
 			}
 		})
 	}
 }
 
-func TestCrawler_WithScraper(t *testing.T) {
+func TestCrawler_CrawlContext_Frontier(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		switch link.URL.Path {
+		case "":
+			return []*url.URL{
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			}, nil
+		case "/a":
+			return []*url.URL{testutil.URLMustParse("http://localhost/a/1")}, nil
+		default:
+			return nil, nil
+		}
+	})
+
 	tests := []struct {
-		name         string
-		givenScraper Scraper
-		want         *Crawler
+		name          string
+		givenFrontier Frontier
+		want          []*url.URL
+		wantErr       error
 	}{
 		{
-			name:         "expect custom Scraper initialize",
-			givenScraper: mockScraper{},
-			want: &Crawler{
-				scraper: mockScraper{},
+			name:          "expect breadth first given a FIFO frontier",
+			givenFrontier: fifo.New(),
+			want: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+				testutil.URLMustParse("http://localhost/a/1"),
 			},
 		},
+		{
+			name:          "expect depth first given a LIFO frontier",
+			givenFrontier: lifo.New(),
+			want: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/b"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/a/1"),
+			},
+		},
+		{
+			name: "expect highest score first given a priority frontier",
+			givenFrontier: priority.New(func(link *page.Link) float64 {
+				return float64(len(link.URL.Path))
+			}),
+			want: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/a/1"),
+				testutil.URLMustParse("http://localhost/b"),
+			},
+		},
+		{
+			name:          "expect frontier error given the frontier fails to push",
+			givenFrontier: &mockFrontier{Frontier: fifo.New(), GivenPushError: errTest, GivenPushAfter: 1},
+			want: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+			},
+			wantErr: ErrFrontier,
+		},
+		{
+			name:          "expect stopped given the frontier fails to push the seeds",
+			givenFrontier: &mockFrontier{Frontier: fifo.New(), GivenPushError: errTest},
+			want:          nil,
+			wantErr:       ErrFrontier,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := New(WithScraper(tt.givenScraper))
-			if !cmp.Equal(got.scraper, tt.want.scraper) {
-				t.Error(cmp.Diff(got.scraper, tt.want.scraper))
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+				WithFrontier(tt.givenFrontier),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got.Visited, tt.want) {
+				t.Error(cmp.Diff(got.Visited, tt.want))
+			}
+
+			if !cmp.Equal(tt.givenFrontier.Len(), 0) {
+				t.Error(cmp.Diff(tt.givenFrontier.Len(), 0))
 			}
 		})
 	}
 }
 
-func TestCrawler_WithStorer(t *testing.T) {
+func TestCrawler_CrawlContext_Persister(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+
 	tests := []struct {
-		name        string
-		givenStorer Storer
-		want        *Crawler
+		name            string
+		givenCancel     bool
+		givenCancelPath string
+		givenOptions    []Option
+		wantErr         error
+		wantQueued      int
 	}{
 		{
-			name:        "expect custom Storer initialize",
-			givenStorer: mockStorer{},
-			want: &Crawler{
-				storer: mockStorer{},
-			},
+			name:        "expect links kept on disk given a cancelled crawl",
+			givenCancel: true,
+			wantErr:     ErrCanceled,
+			wantQueued:  10,
+		},
+		{
+			name:            "expect link in flight kept on disk given a crawl cancelled during a request",
+			givenCancelPath: "/0",
+			wantErr:         ErrCanceled,
+			wantQueued:      10,
+		},
+		{
+			name:         "expect links kept on disk given a crawl stopped by a budget",
+			givenOptions: []Option{WithMaxPages(2)},
+			wantErr:      ErrBudget,
+			wantQueued:   9,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := New(WithStorer(tt.givenStorer))
-			if !cmp.Equal(got.storer, tt.want.storer) {
-				t.Error(cmp.Diff(got.storer, tt.want.storer))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			dir := t.TempDir()
+
+			frontier, err := disk.New(dir, disk.WithSegmentSize(5))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				if link.Depth > 0 {
+					return nil, nil
+				}
+
+				if tt.givenCancel {
+					cancel()
+				}
+
+				urls := make([]*url.URL, 0, 10)
+				for i := 0; i < 10; i++ {
+					urls = append(urls, testutil.URLMustParse(fmt.Sprintf("http://localhost/%d", i)))
+				}
+
+				return urls, nil
+			})
+
+			var requester Requester = mockRequester{GivenRequest: testRequest}
+			if tt.givenCancelPath != "" {
+				requester = mockCancelRequester{GivenPath: tt.givenCancelPath, GivenCancel: cancel}
+			}
+
+			options := append([]Option{
+				WithConcurrency(1),
+				WithRequester(requester),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+				WithFrontier(frontier),
+			}, tt.givenOptions...)
+
+			_, err = New(options...).CrawlContext(ctx, testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) == 0 {
+				t.Error("expected segment files left in the frontier directory")
+			}
+
+			reopened, err := disk.New(dir, disk.WithSegmentSize(5))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(reopened.Len(), tt.wantQueued) {
+				t.Error(cmp.Diff(reopened.Len(), tt.wantQueued))
 			}
 		})
 	}
 }
 
-type mockRequester struct {
-	GivenRequestError error
-	GivenDoError      error
-	GivenRequest      *http.Request
-	GivenReadCloser   io.ReadCloser
-}
+func TestCrawler_Crawl_Errors(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	tests := []struct {
+		name           string
+		givenURL       *url.URL
+		givenRequester Requester
+		givenScraper   Scraper
+		want           Errors
+	}{
+		{
+			name:     "expect every failed URL given multiple failures",
+			givenURL: testutil.URLMustParse("http://localhost"),
+			givenRequester: mockRequester{
+				GivenRequest: testRequest,
+			},
+			givenScraper: mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				if link.Depth > 0 {
+					return nil, errTest
+				}
 
-func (m mockRequester) Request(_ context.Context, _ string, _ io.Reader) (*http.Request, error) {
-	return m.GivenRequest, m.GivenRequestError
-}
+				return []*url.URL{
+					testutil.URLMustParse("http://localhost/foo"),
+					testutil.URLMustParse("http://localhost/bar"),
+				}, nil
+			}),
+			want: Errors{
+				{URL: testutil.URLMustParse("http://localhost/foo"), Stage: StageScraper, Err: errTest},
+				{URL: testutil.URLMustParse("http://localhost/bar"), Stage: StageScraper, Err: errTest},
+			},
+		},
+		{
+			name:     "expect an error given the seed fails",
+			givenURL: testutil.URLMustParse("http://localhost"),
+			givenRequester: mockRequester{
+				GivenRequest: testRequest,
+				GivenDoError: errTest,
+			},
+			givenScraper: mockScraper{},
+			want: Errors{
+				{URL: testutil.URLMustParse("http://localhost"), Stage: StageRequester, Err: errTest},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []error
 
-func (m mockRequester) Do(_ *http.Request) (io.ReadCloser, error) {
-	return m.GivenReadCloser, m.GivenDoError
-}
+			c := New(
+				WithConcurrency(1),
+				WithRequester(tt.givenRequester),
+				WithScraper(tt.givenScraper),
+				WithStorer(mockStorer{}),
+				WithLogger(mockLogger{GivenErrors: &logged}),
+				WithEnforcer(mockEnforcer{GivenBool: true}),
+			)
 
-type mockScraper struct {
-	GivenError error
-	GivenURLs  []*url.URL
-}
+			err := c.Crawl(tt.givenURL)
 
-func (m mockScraper) Scrape(_ *http.Request, _ io.ReadCloser) ([]*url.URL, error) {
-	return m.GivenURLs, m.GivenError
-}
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("expected Errors, got %v", err)
+			}
 
-type mockStorer struct {
-	GivenReadError  error
-	GivenWriteError error
-	GivenNilError   error
-	GivenStore      map[url.URL]bool
-}
+			if !cmp.Equal(got.Error(), tt.want.Error()) {
+				t.Error(cmp.Diff(got.Error(), tt.want.Error()))
+			}
 
-func (m mockStorer) Read() (map[url.URL]bool, error) {
-	return m.GivenStore, m.GivenReadError
+			if !cmp.Equal(len(logged), len(tt.want)) {
+				t.Error(cmp.Diff(len(logged), len(tt.want)))
+			}
+		})
+	}
 }
 
-func (m mockStorer) Write(u *url.URL) error {
-	if u == nil {
-		return m.GivenNilError
+func TestCrawler_Crawl_Status(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	tests := []struct {
+		name            string
+		givenStatusCode int
+		wantErr         error
+	}{
+		{
+			name:            "expect 2xx response to be scraped",
+			givenStatusCode: http.StatusOK,
+			wantErr:         ErrScraper,
+		},
+		{
+			name:            "expect 404 response not to be scraped",
+			givenStatusCode: http.StatusNotFound,
+			wantErr:         nil,
+		},
+		{
+			name:            "expect 500 response not to be scraped",
+			givenStatusCode: http.StatusInternalServerError,
+			wantErr:         nil,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(
+				WithRequester(mockRequester{
+					GivenRequest: testRequest,
+					GivenResponse: &page.Response{
+						Request:    testRequest,
+						URL:        testRequest.URL,
+						StatusCode: tt.givenStatusCode,
+						Body:       io.NopCloser(strings.NewReader("")),
+					},
+				}),
+				WithScraper(mockScraper{GivenError: errTest}),
+				WithStorer(mockStorer{}),
+				WithLogger(mockLogger{}),
+				WithEnforcer(mockEnforcer{GivenBool: true}),
+			)
 
-	return m.GivenWriteError
+			err := c.Crawl(testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+		})
+	}
 }
 
-type mockLogger struct {
-}
+func TestCrawler_Crawl_FailurePolicy(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.Depth > 0 {
+			return nil, errTest
+		}
+
+		return []*url.URL{
+			testutil.URLMustParse("http://localhost/foo"),
+			testutil.URLMustParse("http://localhost/bar"),
+			testutil.URLMustParse("http://localhost/baz"),
+		}, nil
+	})
+
+	tests := []struct {
+		name        string
+		givenPolicy FailurePolicy
+		wantErr     error
+		wantErrors  int
+	}{
+		{
+			name:        "expect every failure given continue on error",
+			givenPolicy: ContinueOnError(),
+			wantErr:     ErrScraper,
+			wantErrors:  3,
+		},
+		{
+			name:        "expect aborted after the first failure given abort on error",
+			givenPolicy: AbortOnError(),
+			wantErr:     ErrAborted,
+			wantErrors:  1,
+		},
+		{
+			name:        "expect aborted after the second failure given abort after two",
+			givenPolicy: AbortAfter(2),
+			wantErr:     ErrAborted,
+			wantErrors:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []error
 
-func (m mockLogger) Error(_ error) {}
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithStorer(mockStorer{}),
+				WithLogger(mockLogger{GivenErrors: &logged}),
+				WithEnforcer(mockEnforcer{GivenBool: true}),
+				WithFailurePolicy(tt.givenPolicy),
+			)
 
-func (m mockLogger) Info(_ *url.URL, _ []*url.URL) {}
+			err := c.Crawl(testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
 
-type mockEnforcer struct {
-	GivenBool bool
-}
+			if !errors.Is(err, ErrScraper) {
+				t.Errorf("expected %v to wrap %v", err, ErrScraper)
+			}
 
-func (m mockEnforcer) Enforce(_ map[url.URL]bool, _ *url.URL) bool {
-	return m.GivenBool
+			if !cmp.Equal(len(logged), tt.wantErrors) {
+				t.Error(cmp.Diff(len(logged), tt.wantErrors))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithConcurrency(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenConcurrency int
+		want             int
+	}{
+		{
+			name:             "expect custom concurrency initialize",
+			givenConcurrency: 2,
+			want:             2,
+		},
+		{
+			name:             "expect default concurrency given less than one",
+			givenConcurrency: 0,
+			want:             defaultConcurrency,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithConcurrency(tt.givenConcurrency))
+			if !cmp.Equal(got.concurrency, tt.want) {
+				t.Error(cmp.Diff(got.concurrency, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithEnforcer(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenEnforcer Enforcer
+		want          *Crawler
+	}{
+		{
+			name:          "expect custom enforcer initialize",
+			givenEnforcer: mockEnforcer{},
+			want: &Crawler{
+				enforcer: mockEnforcer{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithEnforcer(tt.givenEnforcer))
+			if !cmp.Equal(got.enforcer, tt.want.enforcer) {
+				t.Error(cmp.Diff(got.enforcer, tt.want.enforcer))
+			}
+		})
+	}
+}
+
+func TestCrawler_Withlogger(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenLogger Logger
+		want        *Crawler
+	}{
+		{
+			name:        "expect custom logger initialize",
+			givenLogger: mockLogger{},
+			want: &Crawler{
+				logger: mockLogger{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithLogger(tt.givenLogger))
+			if !cmp.Equal(got.logger, tt.want.logger) {
+				t.Error(cmp.Diff(got.logger, tt.want.logger))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithMaxDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenMaxDepth int
+		want          int
+	}{
+		{
+			name:          "expect custom max depth initialize",
+			givenMaxDepth: 2,
+			want:          2,
+		},
+		{
+			name:          "expect seed only max depth initialize",
+			givenMaxDepth: 0,
+			want:          0,
+		},
+		{
+			name:          "expect no max depth given less than zero",
+			givenMaxDepth: -2,
+			want:          noMaxDepth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithMaxDepth(tt.givenMaxDepth))
+			if !cmp.Equal(got.maxDepth, tt.want) {
+				t.Error(cmp.Diff(got.maxDepth, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithLogin(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenErr   error
+		wantCalls  []string
+		wantErr    error
+		wantReport bool
+	}{
+		{
+			name:       "expect login before the seed is fetched",
+			givenErr:   nil,
+			wantCalls:  []string{"login", "http://localhost"},
+			wantErr:    nil,
+			wantReport: true,
+		},
+		{
+			name:       "expect crawl not started given login fails",
+			givenErr:   errTest,
+			wantCalls:  []string{"login"},
+			wantErr:    ErrLogin,
+			wantReport: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+
+			c := New(
+				WithLogin(func(_ context.Context) error {
+					calls = append(calls, "login")
+
+					return tt.givenErr
+				}),
+				WithRequester(mockRequester{GivenRequest: testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)}),
+				WithScraper(mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+					calls = append(calls, link.URL.String())
+
+					return nil, nil
+				})),
+				WithLogger(mockLogger{}),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got != nil, tt.wantReport) {
+				t.Error(cmp.Diff(got != nil, tt.wantReport))
+			}
+
+			if !cmp.Equal(calls, tt.wantCalls) {
+				t.Error(cmp.Diff(calls, tt.wantCalls))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithMaxPerHost(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.URL.Path != "" {
+			return nil, nil
+		}
+
+		var urls []*url.URL
+
+		for i := 0; i < 6; i++ {
+			urls = append(urls, link.URL.ResolveReference(&url.URL{Path: fmt.Sprintf("/%d", i)}))
+		}
+
+		return urls, nil
+	})
+
+	tests := []struct {
+		name            string
+		givenMaxPerHost int
+		want            map[string]int
+	}{
+		{
+			name:            "expect one URL per host at a time",
+			givenMaxPerHost: 1,
+			want:            map[string]int{"a.localhost": 1, "b.localhost": 1},
+		},
+		{
+			name:            "expect two URLs per host at a time",
+			givenMaxPerHost: 2,
+			want:            map[string]int{"a.localhost": 2, "b.localhost": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &mockHostRequester{
+				active: map[string]int{},
+				max:    map[string]int{},
+			}
+
+			c := New(
+				WithConcurrency(8),
+				WithMaxPerHost(tt.givenMaxPerHost),
+				WithRequester(requester),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			)
+
+			err := c.Crawl(testutil.URLMustParse("http://a.localhost"), testutil.URLMustParse("http://b.localhost"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(requester.max, tt.want) {
+				t.Error(cmp.Diff(requester.max, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_Redirect(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenRedirects map[string]string
+		wantVisited    []*url.URL
+		wantScraped    []string
+	}{
+		{
+			name:           "expect redirect to another host not scraped",
+			givenRedirects: map[string]string{"/a": "http://example.com/a"},
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			},
+			wantScraped: []string{"http://localhost", "http://localhost/b"},
+		},
+		{
+			name:           "expect redirect to a visited URL not scraped",
+			givenRedirects: map[string]string{"/a": "http://localhost"},
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			},
+			wantScraped: []string{"http://localhost", "http://localhost/b"},
+		},
+		{
+			name:           "expect redirect to the same page scraped",
+			givenRedirects: map[string]string{"/a": "https://localhost/a/"},
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			},
+			wantScraped: []string{"http://localhost", "https://localhost/a/", "http://localhost/b"},
+		},
+		{
+			name:           "expect redirect target scraped and not crawled again",
+			givenRedirects: map[string]string{"/a": "http://localhost/b"},
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+			},
+			wantScraped: []string{"http://localhost", "http://localhost/b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := &mockRecordScraper{GivenURLs: []*url.URL{
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			}}
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRedirectRequester{GivenRedirects: tt.givenRedirects}),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(got.Visited, tt.wantVisited) {
+				t.Error(cmp.Diff(got.Visited, tt.wantVisited))
+			}
+
+			if !cmp.Equal(scraper.scraped, tt.wantScraped) {
+				t.Error(cmp.Diff(scraper.scraped, tt.wantScraped))
+			}
+		})
+	}
+}
+
+func TestCrawler_Redirect_Scope(t *testing.T) {
+	handlers := []testutil.Handler{
+		{Pattern: "/", HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {}},
+		{Pattern: "/a", HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+			http.Redirect(rw, rr, "http://localhost:8080/c", http.StatusFound)
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		givenSeeds  []*url.URL
+		wantScraped []string
+	}{
+		{
+			name: "expect redirect between seed hosts followed",
+			givenSeeds: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/a"),
+				testutil.URLMustParse("http://localhost:8080/"),
+			},
+			wantScraped: []string{"http://localhost:8080/c", "http://localhost:8080/"},
+		},
+		{
+			name:        "expect redirect out of the scope not followed",
+			givenSeeds:  []*url.URL{testutil.URLMustParse("http://127.0.0.1:8080/a")},
+			wantScraped: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := testutil.NewTestServer(handlers...)
+			defer ts.Close()
+
+			scraper := &mockRecordScraper{}
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(get.New(get.WithSameScopeRedirects())),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+			)
+
+			_, err := c.CrawlContext(context.Background(), tt.givenSeeds...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(scraper.scraped, tt.wantScraped) {
+				t.Error(cmp.Diff(scraper.scraped, tt.wantScraped))
+			}
+		})
+	}
+}
+
+func TestCrawler_Pagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenEnforcer Enforcer
+		wantVisited   []*url.URL
+		wantForms     []string
+	}{
+		{
+			name:          "expect every page of a search form given a query aware enforcer",
+			givenEnforcer: samedomainonce.New(samedomainonce.WithQuery()),
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/search?page=1"),
+				testutil.URLMustParse("http://127.0.0.1:8080/search?page=2"),
+				testutil.URLMustParse("http://127.0.0.1:8080/search?page=3"),
+			},
+			wantForms: []string{"POST page=1", "POST page=2", "POST page=3"},
+		},
+		{
+			name:          "expect only the first page of a search form given the default enforcer",
+			givenEnforcer: samedomainonce.New(),
+			wantVisited: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/search?page=1"),
+			},
+			wantForms: []string{"POST page=1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var forms []string
+
+			ts := testutil.NewTestServer(testutil.Handler{
+				Pattern: "/search",
+				HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+					_ = rr.ParseForm()
+					forms = append(forms, rr.Method+" "+rr.PostForm.Encode())
+
+					_, _ = fmt.Fprint(rw, `<a href="/search?page=1">1</a><a href="/search?page=2">2</a><a href="/search?page=3">3</a>`)
+				},
+			})
+			defer ts.Close()
+
+			c := New(
+				WithConcurrency(1),
+				WithEnforcer(tt.givenEnforcer),
+				WithRequester(method.New(
+					method.WithTemplate(regexp.MustCompile(`/search`), method.Template{QueryAsForm: true}),
+				)),
+				WithLogger(mockLogger{}),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://127.0.0.1:8080/search?page=1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(got.Visited, tt.wantVisited) {
+				t.Error(cmp.Diff(got.Visited, tt.wantVisited))
+			}
+
+			if !cmp.Equal(forms, tt.wantForms) {
+				t.Error(cmp.Diff(forms, tt.wantForms))
+			}
+		})
+	}
+}
+
+func TestCrawler_CrawlContext_Duplicates(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		switch link.URL.Path {
+		case "":
+			return []*url.URL{
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/a"),
+				testutil.URLMustParse("http://localhost/b"),
+			}, nil
+		case "/a":
+			return []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/b"),
+			}, nil
+		default:
+			return nil, nil
+		}
+	})
+
+	tests := []struct {
+		name       string
+		wantPushed int
+	}{
+		{
+			name:       "expect each URL pushed once given duplicate and visited links",
+			wantPushed: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontier := &mockFrontier{Frontier: fifo.New()}
+
+			c := New(
+				WithConcurrency(1),
+				WithFrontier(frontier),
+				WithRequester(mockRequester{GivenRequest: testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			)
+
+			if err := c.Crawl(testutil.URLMustParse("http://localhost")); err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(frontier.pushed, tt.wantPushed) {
+				t.Error(cmp.Diff(frontier.pushed, tt.wantPushed))
+			}
+		})
+	}
+}
+
+func TestCrawler_follow(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenMaxDepth int
+		givenVisited  []*url.URL
+		givenLink     *page.Link
+		givenURLs     []*url.URL
+		want          []*page.Link
+	}{
+		{
+			name:          "expect found URLs to be children of the link",
+			givenMaxDepth: noMaxDepth,
+			givenLink:     page.NewLink(testutil.URLMustParse("http://localhost")),
+			givenURLs: []*url.URL{
+				testutil.URLMustParse("http://localhost/foo"),
+			},
+			want: []*page.Link{
+				{
+					URL:    testutil.URLMustParse("http://localhost/foo"),
+					Parent: testutil.URLMustParse("http://localhost"),
+					Depth:  1,
+				},
+			},
+		},
+		{
+			name:          "expect no links given the link is at max depth",
+			givenMaxDepth: 1,
+			givenLink: &page.Link{
+				URL:    testutil.URLMustParse("http://localhost/foo"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  1,
+			},
+			givenURLs: []*url.URL{
+				testutil.URLMustParse("http://localhost/bar"),
+			},
+			want: nil,
+		},
+		{
+			name:          "expect visited URLs skipped",
+			givenMaxDepth: noMaxDepth,
+			givenVisited:  []*url.URL{testutil.URLMustParse("http://localhost/foo")},
+			givenLink:     page.NewLink(testutil.URLMustParse("http://localhost")),
+			givenURLs: []*url.URL{
+				testutil.URLMustParse("http://localhost/foo"),
+				testutil.URLMustParse("http://localhost/bar"),
+			},
+			want: []*page.Link{
+				{
+					URL:    testutil.URLMustParse("http://localhost/bar"),
+					Parent: testutil.URLMustParse("http://localhost"),
+					Depth:  1,
+				},
+			},
+		},
+		{
+			name:          "expect no links given max depth of zero",
+			givenMaxDepth: 0,
+			givenLink:     page.NewLink(testutil.URLMustParse("http://localhost")),
+			givenURLs: []*url.URL{
+				testutil.URLMustParse("http://localhost/foo"),
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithMaxDepth(tt.givenMaxDepth))
+
+			for _, u := range tt.givenVisited {
+				if err := c.storer.Write(u); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := c.follow(tt.givenLink, tt.givenURLs)
+			if !cmp.Equal(got, tt.want, cmpopts.EquateEmpty()) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithRequester(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenRequester Requester
+		want           *Crawler
+	}{
+		{
+			name:           "expect custom Requester initialize",
+			givenRequester: mockRequester{},
+			want: &Crawler{
+				requester: mockRequester{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithRequester(tt.givenRequester))
+			if !cmp.Equal(got.requester, tt.want.requester) {
+				t.Error(cmp.Diff(got.requester, tt.want.requester))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithLimiter(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	tests := []struct {
+		name         string
+		givenLimiter *mockLimiter
+		want         []*url.URL
+		wantErr      error
+	}{
+		{
+			name:         "expect limiter to wait for every crawled URL",
+			givenLimiter: &mockLimiter{},
+			want:         []*url.URL{testutil.URLMustParse("http://localhost")},
+			wantErr:      nil,
+		},
+		{
+			name:         "expect requester error given limiter error",
+			givenLimiter: &mockLimiter{GivenError: errTest},
+			want:         []*url.URL{testutil.URLMustParse("http://localhost")},
+			wantErr:      ErrRequester,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(mockScraper{}),
+				WithLogger(mockLogger{}),
+				WithLimiter(tt.givenLimiter),
+			)
+
+			err := c.Crawl(testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(tt.givenLimiter.waited, tt.want) {
+				t.Error(cmp.Diff(tt.givenLimiter.waited, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithLimiter_Scheduler(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.Depth > 0 {
+			return nil, nil
+		}
+
+		urls := make([]*url.URL, 0, 4)
+		for i := 0; i < 4; i++ {
+			urls = append(urls, link.URL.ResolveReference(&url.URL{Path: fmt.Sprintf("/%d", i)}))
+		}
+
+		return urls, nil
+	})
+
+	tests := []struct {
+		name           string
+		givenLimiter   *mockSchedulerLimiter
+		wantVisited    int
+		wantMaxWaiting map[string]int
+	}{
+		{
+			name: "expect one worker waiting for a slow host given a Scheduler",
+			givenLimiter: &mockSchedulerLimiter{
+				PerHost: perhost.New(perhost.WithHost("a.localhost", perhost.WithRate(20, 1))),
+				waiting: map[string]int{},
+				max:     map[string]int{},
+			},
+			wantVisited:    10,
+			wantMaxWaiting: map[string]int{"a.localhost": 1, "b.localhost": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(
+				WithConcurrency(4),
+				WithRequester(&mockHostRequester{active: map[string]int{}, max: map[string]int{}}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+				WithLimiter(tt.givenLimiter),
+			)
+
+			got, err := c.CrawlContext(
+				context.Background(),
+				testutil.URLMustParse("http://a.localhost"),
+				testutil.URLMustParse("http://b.localhost"),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(len(got.Visited), tt.wantVisited) {
+				t.Error(cmp.Diff(len(got.Visited), tt.wantVisited))
+			}
+
+			if !cmp.Equal(tt.givenLimiter.max, tt.wantMaxWaiting) {
+				t.Error(cmp.Diff(tt.givenLimiter.max, tt.wantMaxWaiting))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithScraper(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenScraper Scraper
+		want         *Crawler
+	}{
+		{
+			name:         "expect custom Scraper initialize",
+			givenScraper: mockScraper{},
+			want: &Crawler{
+				scraper: mockScraper{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithScraper(tt.givenScraper))
+			if !cmp.Equal(got.scraper, tt.want.scraper) {
+				t.Error(cmp.Diff(got.scraper, tt.want.scraper))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithStorer(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenStorer Storer
+		want        *Crawler
+	}{
+		{
+			name:        "expect custom Storer initialize",
+			givenStorer: mockStorer{},
+			want: &Crawler{
+				storer: mockStorer{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(WithStorer(tt.givenStorer))
+			if !cmp.Equal(got.storer, tt.want.storer) {
+				t.Error(cmp.Diff(got.storer, tt.want.storer))
+			}
+		})
+	}
+}
+
+type mockRequester struct {
+	GivenRequestError error
+	GivenDoError      error
+	GivenRequest      *http.Request
+	GivenResponse     *page.Response
+}
+
+func (m mockRequester) Request(_ context.Context, _ string, _ io.Reader) (*http.Request, error) {
+	return m.GivenRequest, m.GivenRequestError
+}
+
+func (m mockRequester) Do(req *http.Request) (*page.Response, error) {
+	if m.GivenResponse == nil && m.GivenDoError == nil {
+		return &page.Response{Request: req, URL: req.URL, StatusCode: http.StatusOK}, nil
+	}
+
+	return m.GivenResponse, m.GivenDoError
+}
+
+// mockCancelRequester cancels the crawl while requesting the given path.
+type mockCancelRequester struct {
+	GivenPath   string
+	GivenCancel context.CancelFunc
+}
+
+func (m mockCancelRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m mockCancelRequester) Do(req *http.Request) (*page.Response, error) {
+	if req.URL.Path == m.GivenPath {
+		m.GivenCancel()
+
+		return nil, req.Context().Err()
+	}
+
+	return &page.Response{Request: req, URL: req.URL, StatusCode: http.StatusOK}, nil
+}
+
+// mockHostRequester records the most requests in flight at the same time for each host.
+type mockHostRequester struct {
+	mu     sync.Mutex
+	active map[string]int
+	max    map[string]int
+}
+
+func (m *mockHostRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m *mockHostRequester) Do(req *http.Request) (*page.Response, error) {
+	host := req.URL.Hostname()
+
+	m.mu.Lock()
+	m.active[host]++
+	if m.active[host] > m.max[host] {
+		m.max[host] = m.active[host]
+	}
+	m.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	m.mu.Lock()
+	m.active[host]--
+	m.mu.Unlock()
+
+	return &page.Response{Request: req, URL: req.URL, StatusCode: http.StatusOK}, nil
+}
+
+type mockScraper struct {
+	GivenError error
+	GivenURLs  []*url.URL
+}
+
+func (m mockScraper) Scrape(_ *page.Link, _ *page.Response) ([]*url.URL, error) {
+	return m.GivenURLs, m.GivenError
+}
+
+// mockRedirectRequester redirects each path to its given URL.
+type mockRedirectRequester struct {
+	GivenRedirects map[string]string
+}
+
+func (m mockRedirectRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m mockRedirectRequester) Do(req *http.Request) (*page.Response, error) {
+	resp := &page.Response{Request: req, URL: req.URL, StatusCode: http.StatusOK}
+
+	if target, ok := m.GivenRedirects[req.URL.Path]; ok {
+		resp.URL = testutil.URLMustParse(target)
+		resp.Redirects = []*url.URL{req.URL}
+	}
+
+	return resp, nil
+}
+
+// mockRecordScraper records the URL of each scraped response and returns the given URLs from the seed.
+type mockRecordScraper struct {
+	GivenURLs []*url.URL
+	scraped   []string
+}
+
+func (m *mockRecordScraper) Scrape(link *page.Link, resp *page.Response) ([]*url.URL, error) {
+	m.scraped = append(m.scraped, resp.URL.String())
+
+	if link.Depth > 0 {
+		return nil, nil
+	}
+
+	return m.GivenURLs, nil
+}
+
+type mockScraperFunc func(link *page.Link) ([]*url.URL, error)
+
+func (m mockScraperFunc) Scrape(link *page.Link, _ *page.Response) ([]*url.URL, error) {
+	return m(link)
+}
+
+type mockStorer struct {
+	GivenReadError  error
+	GivenWriteError error
+	GivenNilError   error
+	GivenStore      map[url.URL]bool
+}
+
+func (m mockStorer) Read() (map[url.URL]bool, error) {
+	return m.GivenStore, m.GivenReadError
+}
+
+func (m mockStorer) Write(u *url.URL) error {
+	if u == nil {
+		return m.GivenNilError
+	}
+
+	return m.GivenWriteError
+}
+
+type mockLogger struct {
+	GivenErrors *[]error
+}
+
+func (m mockLogger) Error(err error) {
+	if m.GivenErrors != nil {
+		*m.GivenErrors = append(*m.GivenErrors, err)
+	}
+}
+
+func (m mockLogger) Info(_ *page.Link, _ *page.Response, _ []*url.URL) {}
+
+type mockEnforcer struct {
+	GivenBool bool
+}
+
+func (m mockEnforcer) Enforce(_ map[url.URL]bool, _ *page.Link) bool {
+	return m.GivenBool
+}
+
+type mockLimiter struct {
+	GivenError error
+	waited     []*url.URL
+}
+
+func (m *mockLimiter) Wait(_ context.Context, u *url.URL) error {
+	m.waited = append(m.waited, u)
+
+	return m.GivenError
+}
+
+type mockSchedulerLimiter struct {
+	*perhost.PerHost
+	mu      sync.Mutex
+	waiting map[string]int
+	max     map[string]int
+}
+
+func (m *mockSchedulerLimiter) Wait(ctx context.Context, u *url.URL) error {
+	host := u.Hostname()
+
+	m.mu.Lock()
+	m.waiting[host]++
+	if m.waiting[host] > m.max[host] {
+		m.max[host] = m.waiting[host]
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.waiting[host]--
+		m.mu.Unlock()
+	}()
+
+	return m.PerHost.Wait(ctx, u)
+}
+
+type mockFrontier struct {
+	Frontier
+	GivenPushError error
+	GivenPushAfter int
+	pushed         int
+}
+
+func (m *mockFrontier) Push(link *page.Link) error {
+	if m.pushed >= m.GivenPushAfter && m.GivenPushError != nil {
+		return m.GivenPushError
+	}
+
+	m.pushed++
+
+	return m.Frontier.Push(link)
 }
@@ -3,24 +3,43 @@ package samedomainonce
 import (
 	"net/url"
 	"strings"
+
+	"github.com/clarke94/crawler/page"
 )
 
 // SameDomainOnce is an Enforcer that enforces same domain and only visit once.
-type SameDomainOnce struct{}
+type SameDomainOnce struct {
+	query bool
+}
 
-// New initializes a new SameDomainOnce Enforcer.
-func New() *SameDomainOnce {
-	return &SameDomainOnce{}
+// Option is a functional option to modify the default SameDomainOnce instance.
+type Option func(s *SameDomainOnce)
+
+// WithQuery treats URLs with the same path and a different query as different pages,
+// such as the pages of a search. By default the query is ignored so a path is only visited once.
+func WithQuery() Option {
+	return func(s *SameDomainOnce) {
+		s.query = true
+	}
 }
 
-// Enforce enforces same URL domains and only visit once and checks equality without trailing suffix.
-func (s *SameDomainOnce) Enforce(visited map[url.URL]bool, u *url.URL) bool {
-	visitedDomain := getDomain(visited)
-	if isNothingVisited(visitedDomain) {
-		return true
+// New initializes a new SameDomainOnce Enforcer.
+func New(options ...Option) *SameDomainOnce {
+	s := &SameDomainOnce{}
+
+	for _, opt := range options {
+		opt(s)
 	}
 
-	if !isDomainEqual(*visitedDomain, u.Hostname()) {
+	return s
+}
+
+// Enforce enforces URL domains within the scope of the seed URLs and only visit once
+// and checks equality without trailing suffix, and of the query with WithQuery.
+func (s *SameDomainOnce) Enforce(visited map[url.URL]bool, link *page.Link) bool {
+	u := link.URL
+
+	if !link.Scope.Contains(u) {
 		return false
 	}
 
@@ -28,30 +47,13 @@ func (s *SameDomainOnce) Enforce(visited map[url.URL]bool, u *url.URL) bool {
 		return false
 	}
 
-	if isPathEqual(visited, u) {
+	if isPathEqual(visited, u, s.query) {
 		return false
 	}
 
 	return true
 }
 
-func getDomain(urls map[url.URL]bool) *string {
-	for k := range urls {
-		hn := k.Hostname()
-		return &hn
-	}
-
-	return nil
-}
-
-func isNothingVisited(u *string) bool {
-	return u == nil
-}
-
-func isDomainEqual(visited, found string) bool {
-	return visited == found
-}
-
 func isVisited(visited map[url.URL]bool, found *url.URL) bool {
 	if _, ok := visited[*found]; ok {
 		return true
@@ -60,8 +62,16 @@ func isVisited(visited map[url.URL]bool, found *url.URL) bool {
 	return false
 }
 
-func isPathEqual(visited map[url.URL]bool, found *url.URL) bool {
+func isPathEqual(visited map[url.URL]bool, found *url.URL, query bool) bool {
 	for k := range visited {
+		if k.Hostname() != found.Hostname() {
+			continue
+		}
+
+		if query && k.Query().Encode() != found.Query().Encode() {
+			continue
+		}
+
 		if strings.TrimSuffix(k.Path, "/") == strings.TrimSuffix(found.Path, "/") {
 			return true
 		}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
 	"github.com/clarke94/crawler/storage/memory"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -32,10 +33,12 @@ func TestNewSameDomain(t *testing.T) {
 
 func TestSameDomain_Enforce(t *testing.T) {
 	tests := []struct {
-		name      string
-		givenData map[url.URL]bool
-		givenURL  url.URL
-		want      bool
+		name         string
+		givenOptions []Option
+		givenData    map[url.URL]bool
+		givenScope   page.Scope
+		givenURL     url.URL
+		want         bool
 	}{
 		{
 			name:      "expect enforcer true given no URL has been visited",
@@ -56,16 +59,49 @@ func TestSameDomain_Enforce(t *testing.T) {
 			givenData: map[url.URL]bool{
 				*testutil.URLMustParse("http://localhost"): true,
 			},
-			givenURL: *testutil.URLMustParse("https://example.com"),
-			want:     false,
+			givenScope: page.NewScope(testutil.URLMustParse("http://localhost")),
+			givenURL:   *testutil.URLMustParse("https://example.com"),
+			want:       false,
 		},
 		{
 			name: "expect enforcer false given a URL with a different sub-domain",
 			givenData: map[url.URL]bool{
 				*testutil.URLMustParse("https://sub.example.com"): true,
 			},
+			givenScope: page.NewScope(testutil.URLMustParse("https://sub.example.com")),
+			givenURL:   *testutil.URLMustParse("https://example.com"),
+			want:       false,
+		},
+		{
+			name: "expect enforcer true given a URL with the domain of another seed",
+			givenData: map[url.URL]bool{
+				*testutil.URLMustParse("http://localhost"): true,
+			},
+			givenScope: page.NewScope(
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("https://example.com"),
+			),
 			givenURL: *testutil.URLMustParse("https://example.com"),
-			want:     false,
+			want:     true,
+		},
+		{
+			name:       "expect enforcer false given no URL visited and a URL out of scope",
+			givenData:  nil,
+			givenScope: page.NewScope(testutil.URLMustParse("http://localhost")),
+			givenURL:   *testutil.URLMustParse("https://example.com"),
+			want:       false,
+		},
+		{
+			name: "expect enforcer true given the same path visited on another seed domain",
+			givenData: map[url.URL]bool{
+				*testutil.URLMustParse("http://localhost/about"): true,
+			},
+			givenScope: page.NewScope(
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("https://example.com"),
+			),
+			givenURL: *testutil.URLMustParse("https://example.com/about"),
+			want:     true,
 		},
 		{
 			name: "expect enforcer true given URL with a different protocol",
@@ -131,12 +167,33 @@ func TestSameDomain_Enforce(t *testing.T) {
 			givenURL: *testutil.URLMustParse("https://example.com/foo?foo=bar&bar=foo"),
 			want:     false,
 		},
+		{
+			name:         "expect enforcer true given the same path with another query and WithQuery",
+			givenOptions: []Option{WithQuery()},
+			givenData: map[url.URL]bool{
+				*testutil.URLMustParse("https://example.com/search?page=1"): true,
+			},
+			givenURL: *testutil.URLMustParse("https://example.com/search/?page=2"),
+			want:     true,
+		},
+		{
+			name:         "expect enforcer false given the same query in another order and WithQuery",
+			givenOptions: []Option{WithQuery()},
+			givenData: map[url.URL]bool{
+				*testutil.URLMustParse("https://example.com/search?q=go&page=1"): true,
+			},
+			givenURL: *testutil.URLMustParse("https://example.com/search/?page=1&q=go"),
+			want:     false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := New()
+			s := New(tt.givenOptions...)
+
+			link := page.NewLink(&tt.givenURL)
+			link.Scope = tt.givenScope
 
-			got := s.Enforce(tt.givenData, &tt.givenURL)
+			got := s.Enforce(tt.givenData, link)
 
 			if !cmp.Equal(got, tt.want) {
 				t.Error(cmp.Diff(got, tt.want))
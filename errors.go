@@ -0,0 +1,150 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Stage is the step of crawling a URL at which an error occurred.
+type Stage int
+
+const (
+	// StageStorer is an error reading from or writing to the Storer.
+	StageStorer Stage = iota + 1
+	// StageRequester is an error creating or sending a request with the Requester.
+	StageRequester
+	// StageScraper is an error scraping a response with the Scraper.
+	StageScraper
+	// StageFrontier is an error pushing a found URL to the Frontier.
+	StageFrontier
+)
+
+// Err returns the annotated error for the stage.
+func (s Stage) Err() error {
+	switch s {
+	case StageRequester:
+		return ErrRequester
+	case StageScraper:
+		return ErrScraper
+	case StageStorer:
+		return ErrStorer
+	case StageFrontier:
+		return ErrFrontier
+	default:
+		return nil
+	}
+}
+
+// String returns the name of the stage.
+func (s Stage) String() string {
+	switch s {
+	case StageRequester:
+		return "requester"
+	case StageScraper:
+		return "scraper"
+	case StageStorer:
+		return "storer"
+	case StageFrontier:
+		return "frontier"
+	default:
+		return "unknown"
+	}
+}
+
+// CrawlError is an error that occurred while crawling a single URL.
+type CrawlError struct {
+	URL   *url.URL
+	Stage Stage
+	Err   error
+}
+
+// Error returns the stage, URL and cause of the error.
+func (e *CrawlError) Error() string {
+	return fmt.Sprintf("%v: %s: %v", e.Stage.Err(), e.URL, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *CrawlError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the annotated error for the stage.
+func (e *CrawlError) Is(target error) bool {
+	return target != nil && target == e.Stage.Err()
+}
+
+// StopError is the error returned when a crawl stops before every queued URL is crawled.
+type StopError struct {
+	// Reason is why the crawl stopped, such as ErrAborted or ErrCanceled.
+	Reason error
+	// Cause is the underlying error that stopped the crawl, such as the context error.
+	Cause error
+	// Errors is the collection of errors that occurred before the crawl stopped.
+	Errors Errors
+}
+
+// Error returns the reason and the errors that occurred.
+func (e *StopError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Reason.Error()
+	}
+
+	return fmt.Sprintf("%v: %v", e.Reason, e.Errors)
+}
+
+// Is reports whether the target is the reason or the cause the crawl stopped.
+func (e *StopError) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+
+	return target == e.Reason || (e.Cause != nil && errors.Is(e.Cause, target))
+}
+
+// Unwrap returns the errors that occurred before the crawl stopped.
+func (e *StopError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e.Errors
+}
+
+// Errors is the collection of errors that occurred during a crawl.
+type Errors []*CrawlError
+
+// Error returns all the errors in the collection.
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any error in the collection matches the target.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in the collection that matches the target.
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,175 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+var errTest = errors.New("test error")
+
+func TestCrawlError_Is(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenError  *CrawlError
+		givenTarget error
+		want        bool
+	}{
+		{
+			name: "expect requester stage to match requester error",
+			givenError: &CrawlError{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Stage: StageRequester,
+				Err:   errTest,
+			},
+			givenTarget: ErrRequester,
+			want:        true,
+		},
+		{
+			name: "expect scraper stage not to match requester error",
+			givenError: &CrawlError{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Stage: StageScraper,
+				Err:   errTest,
+			},
+			givenTarget: ErrRequester,
+			want:        false,
+		},
+		{
+			name: "expect storer stage to match storer error",
+			givenError: &CrawlError{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Stage: StageStorer,
+				Err:   errTest,
+			},
+			givenTarget: ErrStorer,
+			want:        true,
+		},
+		{
+			name: "expect cause to match",
+			givenError: &CrawlError{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Stage: StageScraper,
+				Err:   errTest,
+			},
+			givenTarget: errTest,
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.Is(tt.givenError, tt.givenTarget)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawlError_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenError *CrawlError
+		want       string
+	}{
+		{
+			name: "expect stage, URL and cause",
+			givenError: &CrawlError{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Stage: StageRequester,
+				Err:   errTest,
+			},
+			want: "requester error: http://localhost: test error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.givenError.Error()
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestErrors_Is(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenErrors Errors
+		givenTarget error
+		want        bool
+	}{
+		{
+			name: "expect match given any error matches",
+			givenErrors: Errors{
+				{URL: testutil.URLMustParse("http://localhost/foo"), Stage: StageScraper, Err: errTest},
+				{URL: testutil.URLMustParse("http://localhost/bar"), Stage: StageRequester, Err: errTest},
+			},
+			givenTarget: ErrRequester,
+			want:        true,
+		},
+		{
+			name: "expect no match given no error matches",
+			givenErrors: Errors{
+				{URL: testutil.URLMustParse("http://localhost/foo"), Stage: StageScraper, Err: errTest},
+			},
+			givenTarget: ErrStorer,
+			want:        false,
+		},
+		{
+			name:        "expect no match given no errors",
+			givenErrors: Errors{},
+			givenTarget: ErrStorer,
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.Is(tt.givenErrors, tt.givenTarget)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestErrors_As(t *testing.T) {
+	first := &CrawlError{URL: testutil.URLMustParse("http://localhost/foo"), Stage: StageScraper, Err: errTest}
+	second := &CrawlError{URL: testutil.URLMustParse("http://localhost/bar"), Stage: StageRequester, Err: errTest}
+
+	tests := []struct {
+		name        string
+		givenErrors Errors
+		want        *CrawlError
+		wantOK      bool
+	}{
+		{
+			name:        "expect first crawl error",
+			givenErrors: Errors{first, second},
+			want:        first,
+			wantOK:      true,
+		},
+		{
+			name:        "expect no crawl error given no errors",
+			givenErrors: Errors{},
+			want:        nil,
+			wantOK:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *CrawlError
+
+			ok := errors.As(tt.givenErrors, &got)
+			if !cmp.Equal(ok, tt.wantOK) {
+				t.Error(cmp.Diff(ok, tt.wantOK))
+			}
+
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package disk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/clarke94/crawler/page"
+)
+
+var (
+	// ErrInvalidLink is returned when a nil link is pushed.
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrEmpty is returned when popping from an empty Disk.
+	ErrEmpty = errors.New("frontier is empty")
+	// ErrInvalidSegmentSize is returned when the segment size is less than one.
+	ErrInvalidSegmentSize = errors.New("invalid segment size")
+)
+
+const (
+	// defaultSegmentSize is the number of links in a segment when WithSegmentSize is not provided.
+	defaultSegmentSize = 10000
+	segmentPrefix      = "segment-"
+	segmentSuffix      = ".jsonl"
+	filePerm           = 0o600
+	dirPerm            = 0o700
+)
+
+// Disk is a Frontier that pops links in the order they were pushed and spills
+// them to a log of segment files in a directory, so at most two segments of
+// links are held in memory at a time.
+//
+// Segments left in the directory by a previous Disk are popped first. Persist
+// and Close write the links still in memory to the directory so they are not
+// lost, the crawler does not empty a Disk when a crawl stops early and calls
+// Persist once the crawl ends and for every checkpoint.
+//
+// Once Persist has been called the segment files of popped links are only
+// removed by the next Persist, so if the process is killed the directory still
+// holds every link that was waiting at the last Persist.
+type Disk struct {
+	dir         string
+	segmentSize int
+
+	head     []*page.Link
+	headSeq  int
+	onDisk   bool
+	tail     []*page.Link
+	segments []int
+	next     int
+	length   int
+
+	persisted bool
+	popped    []int
+}
+
+// Option is a functional option to modify the default Disk instance.
+type Option func(d *Disk)
+
+// WithSegmentSize sets the number of links written to each segment file,
+// it is the number of links held in memory by the head and the tail of the queue.
+func WithSegmentSize(n int) Option {
+	return func(d *Disk) {
+		d.segmentSize = n
+	}
+}
+
+// New initializes a new Disk Frontier in the given directory, creating it
+// if it does not exist and loading any segments already in it.
+func New(dir string, options ...Option) (*Disk, error) {
+	d := &Disk{
+		dir:         dir,
+		segmentSize: defaultSegmentSize,
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	if d.segmentSize < 1 {
+		return nil, ErrInvalidSegmentSize
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, err
+	}
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Push adds the link to the back of the queue, writing the tail of the
+// queue to a new segment once it is full.
+func (d *Disk) Push(link *page.Link) error {
+	if link == nil {
+		return ErrInvalidLink
+	}
+
+	d.tail = append(d.tail, link)
+	d.length++
+
+	if len(d.tail) < d.segmentSize {
+		return nil
+	}
+
+	return d.flush()
+}
+
+// Pop removes and returns the link at the front of the queue, reading
+// the oldest segment once the head of the queue is empty.
+func (d *Disk) Pop() (*page.Link, error) {
+	if d.length == 0 {
+		return nil, ErrEmpty
+	}
+
+	if len(d.head) == 0 {
+		if err := d.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	link := d.head[0]
+	d.head[0] = nil
+	d.head = d.head[1:]
+	d.length--
+
+	if len(d.head) == 0 && d.onDisk {
+		d.onDisk = false
+
+		if err := d.remove(d.headSeq); err != nil {
+			return nil, err
+		}
+	}
+
+	return link, nil
+}
+
+// Len returns the number of links in the queue.
+func (d *Disk) Len() int {
+	return d.length
+}
+
+// Snapshot returns the links in the order they are popped,
+// every segment is read so the links are all held in memory.
+func (d *Disk) Snapshot() ([]*page.Link, error) {
+	links := make([]*page.Link, 0, d.length)
+	links = append(links, d.head...)
+
+	for _, seq := range d.segments {
+		segment, err := d.read(seq)
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, segment...)
+	}
+
+	return append(links, d.tail...), nil
+}
+
+// Persist writes the links held in memory to the directory and removes the
+// segment files popped since the last Persist, the Disk can still be used afterwards.
+func (d *Disk) Persist() error {
+	if len(d.head) > 0 {
+		if err := d.write(d.headSeq, d.head); err != nil {
+			return err
+		}
+
+		d.onDisk = true
+	}
+
+	if len(d.tail) > 0 {
+		if err := d.flush(); err != nil {
+			return err
+		}
+	}
+
+	for len(d.popped) > 0 {
+		if err := os.Remove(d.path(d.popped[0])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		d.popped = d.popped[1:]
+	}
+
+	d.persisted = true
+
+	return nil
+}
+
+// Close writes the links held in memory to the directory,
+// the Disk must not be used after it is closed.
+func (d *Disk) Close() error {
+	return d.Persist()
+}
+
+// advance moves the oldest links to the head of the queue, the segment file
+// is removed once every link read from it has been popped. Links moved from
+// the tail are given the next segment sequence so they keep their place if
+// they are written by Close.
+func (d *Disk) advance() error {
+	if len(d.segments) == 0 {
+		d.head, d.tail = d.tail, nil
+		d.headSeq = d.next
+		d.onDisk = false
+		d.next++
+
+		return nil
+	}
+
+	seq := d.segments[0]
+
+	links, err := d.read(seq)
+	if err != nil {
+		return err
+	}
+
+	d.segments = d.segments[1:]
+	d.head = links
+	d.headSeq = seq
+	d.onDisk = true
+
+	if len(links) == 0 {
+		return d.advance()
+	}
+
+	return nil
+}
+
+// remove removes the segment file once every link in it has been popped,
+// it is left until the next Persist once the Disk has been persisted.
+func (d *Disk) remove(seq int) error {
+	if d.persisted {
+		d.popped = append(d.popped, seq)
+
+		return nil
+	}
+
+	return os.Remove(d.path(seq))
+}
+
+// flush writes the tail of the queue to a new segment file.
+func (d *Disk) flush() error {
+	if err := d.write(d.next, d.tail); err != nil {
+		return err
+	}
+
+	d.segments = append(d.segments, d.next)
+	d.next++
+	d.tail = nil
+
+	return nil
+}
+
+// write replaces the segment file with the given links, the links are written
+// to a temporary file first so a segment is never partially written.
+func (d *Disk) write(seq int, links []*page.Link) error {
+	tmp := d.path(seq) + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	for _, link := range links {
+		if err := enc.Encode(link); err != nil {
+			_ = f.Close()
+
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, d.path(seq))
+}
+
+// read decodes every link in the segment file.
+func (d *Disk) read(seq int) ([]*page.Link, error) {
+	f, err := os.Open(d.path(seq))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var links []*page.Link
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+
+	for dec.More() {
+		link := &page.Link{}
+
+		if err := dec.Decode(link); err != nil {
+			return nil, err
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// load finds the segment files in the directory and counts their links.
+func (d *Disk) load() error {
+	entries, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		var seq int
+
+		name := entry.Name()
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		if _, err := fmt.Sscanf(strings.TrimSuffix(name, segmentSuffix), segmentPrefix+"%d", &seq); err != nil {
+			continue
+		}
+
+		d.segments = append(d.segments, seq)
+	}
+
+	sort.Ints(d.segments)
+
+	for _, seq := range d.segments {
+		links, err := d.read(seq)
+		if err != nil {
+			return err
+		}
+
+		d.length += len(links)
+
+		if seq >= d.next {
+			d.next = seq + 1
+		}
+	}
+
+	return nil
+}
+
+func (d *Disk) path(seq int) string {
+	return filepath.Join(d.dir, fmt.Sprintf("%s%020d%s", segmentPrefix, seq, segmentSuffix))
+}
@@ -0,0 +1,441 @@
+package disk
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenSegmentSize int
+		wantErr          error
+	}{
+		{
+			name:             "expect Disk Frontier to initialize",
+			givenSegmentSize: 1,
+			wantErr:          nil,
+		},
+		{
+			name:             "expect error given a segment size less than one",
+			givenSegmentSize: 0,
+			wantErr:          ErrInvalidSegmentSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(t.TempDir(), WithSegmentSize(tt.givenSegmentSize))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestDisk_Pop(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenSegmentSize int
+		givenLinks       int
+	}{
+		{
+			name:             "expect links in the order they were pushed given they fit in memory",
+			givenSegmentSize: 10,
+			givenLinks:       5,
+		},
+		{
+			name:             "expect links in the order they were pushed given they spill to segments",
+			givenSegmentSize: 2,
+			givenLinks:       7,
+		},
+		{
+			name:             "expect links in the order they were pushed given a segment per link",
+			givenSegmentSize: 1,
+			givenLinks:       3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			d, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := pushLinks(t, d, 0, tt.givenLinks)
+
+			if len(d.head)+len(d.tail) > 2*tt.givenSegmentSize {
+				t.Errorf("expected at most %d links in memory, got %d", 2*tt.givenSegmentSize, len(d.head)+len(d.tail))
+			}
+
+			got := popLinks(t, d)
+			if !cmp.Equal(got, want) {
+				t.Error(cmp.Diff(got, want))
+			}
+
+			_, err = d.Pop()
+			if !cmp.Equal(err, ErrEmpty, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrEmpty, cmpopts.EquateErrors()))
+			}
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(len(files), 0) {
+				t.Error(cmp.Diff(len(files), 0))
+			}
+		})
+	}
+}
+
+func TestDisk_Close(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenSegmentSize int
+		givenPushed      int
+		givenPopped      int
+		givenPushedAfter int
+	}{
+		{
+			name:             "expect remaining links after reopening given links only in memory",
+			givenSegmentSize: 10,
+			givenPushed:      5,
+			givenPopped:      2,
+			givenPushedAfter: 2,
+		},
+		{
+			name:             "expect remaining links after reopening given a partially popped segment",
+			givenSegmentSize: 2,
+			givenPushed:      7,
+			givenPopped:      3,
+			givenPushedAfter: 3,
+		},
+		{
+			name:             "expect no links after reopening given every link popped",
+			givenSegmentSize: 2,
+			givenPushed:      4,
+			givenPopped:      4,
+			givenPushedAfter: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			d, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := pushLinks(t, d, 0, tt.givenPushed)
+
+			for i := 0; i < tt.givenPopped; i++ {
+				if _, err := d.Pop(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want = append(want[tt.givenPopped:], pushLinks(t, d, tt.givenPushed, tt.givenPushedAfter)...)
+
+			if err := d.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reopened, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(reopened.Len(), len(want)) {
+				t.Error(cmp.Diff(reopened.Len(), len(want)))
+			}
+
+			got := popLinks(t, reopened)
+			if !cmp.Equal(got, want, cmpopts.EquateEmpty()) {
+				t.Error(cmp.Diff(got, want, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
+
+func TestDisk_Persist(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenSegmentSize int
+		givenPushed      int
+		givenPopped      int
+	}{
+		{
+			name:             "expect remaining links after reopening given links only in memory",
+			givenSegmentSize: 10,
+			givenPushed:      5,
+			givenPopped:      2,
+		},
+		{
+			name:             "expect remaining links after reopening given a partially popped segment",
+			givenSegmentSize: 2,
+			givenPushed:      7,
+			givenPopped:      3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			d, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := pushLinks(t, d, 0, tt.givenPushed)
+
+			for i := 0; i < tt.givenPopped; i++ {
+				if _, err := d.Pop(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want = want[tt.givenPopped:]
+
+			if err := d.Persist(); err != nil {
+				t.Fatal(err)
+			}
+
+			reopened, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot, err := reopened.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(snapshot))
+			for _, link := range snapshot {
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, want) {
+				t.Error(cmp.Diff(got, want))
+			}
+
+			got = popLinks(t, d)
+			if !cmp.Equal(got, want) {
+				t.Error(cmp.Diff(got, want))
+			}
+
+			if err := d.Persist(); err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(len(files), 0) {
+				t.Error(cmp.Diff(len(files), 0))
+			}
+		})
+	}
+}
+
+func TestDisk_Persist_Popped(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenSegmentSize int
+		givenPushed      int
+		givenPopped      int
+	}{
+		{
+			name:             "expect links at the last persist after reopening given popped segments",
+			givenSegmentSize: 2,
+			givenPushed:      6,
+			givenPopped:      4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			d, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := pushLinks(t, d, 0, tt.givenPushed)
+
+			if err := d.Persist(); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < tt.givenPopped; i++ {
+				if _, err := d.Pop(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reopened, err := New(dir, WithSegmentSize(tt.givenSegmentSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := popLinks(t, reopened)
+			if !cmp.Equal(got, want) {
+				t.Error(cmp.Diff(got, want))
+			}
+		})
+	}
+}
+
+func TestDisk_Push(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *page.Link
+		wantLen   int
+		wantErr   error
+	}{
+		{
+			name:      "expect link pushed",
+			givenLink: page.NewLink(testutil.URLMustParse("http://localhost")),
+			wantLen:   1,
+			wantErr:   nil,
+		},
+		{
+			name:      "expect error given a nil link",
+			givenLink: nil,
+			wantLen:   0,
+			wantErr:   ErrInvalidLink,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.Push(tt.givenLink)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(d.Len(), tt.wantLen) {
+				t.Error(cmp.Diff(d.Len(), tt.wantLen))
+			}
+		})
+	}
+}
+
+func pushLinks(t *testing.T, d *Disk, from, n int) []string {
+	t.Helper()
+
+	var pushed []string
+
+	for i := from; i < from+n; i++ {
+		rawURL := fmt.Sprintf("http://localhost/%d", i)
+
+		if err := d.Push(page.NewLink(testutil.URLMustParse(rawURL))); err != nil {
+			t.Fatal(err)
+		}
+
+		pushed = append(pushed, rawURL)
+	}
+
+	return pushed
+}
+
+func popLinks(t *testing.T, d *Disk) []string {
+	t.Helper()
+
+	var popped []string
+
+	for d.Len() > 0 {
+		link, err := d.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		popped = append(popped, link.URL.String())
+	}
+
+	return popped
+}
+
+func TestDisk_Snapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenLinks []string
+		want       []string
+	}{
+		{
+			name:       "expect links restored in the same order given a snapshot pushed to a new Disk",
+			givenLinks: []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+			want:       []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+		},
+		{
+			name:       "expect no links given nothing pushed",
+			givenLinks: nil,
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(t.TempDir(), WithSegmentSize(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, rawURL := range tt.givenLinks {
+				if err := f.Push(page.NewLink(testutil.URLMustParse(rawURL))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			links, err := f.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(f.Len(), len(tt.givenLinks)) {
+				t.Error(cmp.Diff(f.Len(), len(tt.givenLinks)))
+			}
+
+			restored, err := New(t.TempDir(), WithSegmentSize(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, link := range links {
+				if err := restored.Push(link); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []string
+
+			for restored.Len() > 0 {
+				link, err := restored.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package fifo
+
+import (
+	"errors"
+
+	"github.com/clarke94/crawler/page"
+)
+
+var (
+	// ErrInvalidLink is returned when a nil link is pushed.
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrEmpty is returned when popping from an empty FIFO.
+	ErrEmpty = errors.New("frontier is empty")
+)
+
+// FIFO is a Frontier that pops links in the order they were pushed, crawling breadth first.
+type FIFO struct {
+	links []*page.Link
+}
+
+// New initializes a new FIFO Frontier.
+func New() *FIFO {
+	return &FIFO{}
+}
+
+// Push adds the link to the back of the queue.
+func (f *FIFO) Push(link *page.Link) error {
+	if link == nil {
+		return ErrInvalidLink
+	}
+
+	f.links = append(f.links, link)
+
+	return nil
+}
+
+// Pop removes and returns the link at the front of the queue.
+func (f *FIFO) Pop() (*page.Link, error) {
+	if len(f.links) == 0 {
+		return nil, ErrEmpty
+	}
+
+	link := f.links[0]
+	f.links[0] = nil
+	f.links = f.links[1:]
+
+	return link, nil
+}
+
+// Len returns the number of links in the queue.
+func (f *FIFO) Len() int {
+	return len(f.links)
+}
+
+// Snapshot returns a copy of the links in the order they are popped.
+func (f *FIFO) Snapshot() ([]*page.Link, error) {
+	links := make([]*page.Link, len(f.links))
+	copy(links, f.links)
+
+	return links, nil
+}
@@ -0,0 +1,182 @@
+package fifo
+
+import (
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNewFIFO(t *testing.T) {
+	tests := []struct {
+		name string
+		want *FIFO
+	}{
+		{
+			name: "expect FIFO Frontier to initialize",
+			want: &FIFO{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			if !cmp.Equal(got, tt.want, cmpopts.IgnoreUnexported(FIFO{})) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.IgnoreUnexported(FIFO{})))
+			}
+		})
+	}
+}
+
+func TestFIFO_Pop(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenLinks []string
+		want       []string
+	}{
+		{
+			name:       "expect links popped in the order they were pushed",
+			givenLinks: []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+			want:       []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+		},
+		{
+			name:       "expect no links given nothing pushed",
+			givenLinks: nil,
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New()
+
+			for _, rawURL := range tt.givenLinks {
+				if err := f.Push(page.NewLink(testutil.URLMustParse(rawURL))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !cmp.Equal(f.Len(), len(tt.givenLinks)) {
+				t.Error(cmp.Diff(f.Len(), len(tt.givenLinks)))
+			}
+
+			var got []string
+
+			for f.Len() > 0 {
+				link, err := f.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+
+			_, err := f.Pop()
+			if !cmp.Equal(err, ErrEmpty, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrEmpty, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestFIFO_Push(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *page.Link
+		wantLen   int
+		wantErr   error
+	}{
+		{
+			name:      "expect link pushed",
+			givenLink: page.NewLink(testutil.URLMustParse("http://localhost")),
+			wantLen:   1,
+			wantErr:   nil,
+		},
+		{
+			name:      "expect error given a nil link",
+			givenLink: nil,
+			wantLen:   0,
+			wantErr:   ErrInvalidLink,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New()
+
+			err := f.Push(tt.givenLink)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(f.Len(), tt.wantLen) {
+				t.Error(cmp.Diff(f.Len(), tt.wantLen))
+			}
+		})
+	}
+}
+
+func TestFIFO_Snapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenLinks []string
+		want       []string
+	}{
+		{
+			name:       "expect links restored in the same order given a snapshot pushed to a new FIFO",
+			givenLinks: []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+			want:       []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+		},
+		{
+			name:       "expect no links given nothing pushed",
+			givenLinks: nil,
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New()
+
+			for _, rawURL := range tt.givenLinks {
+				if err := f.Push(page.NewLink(testutil.URLMustParse(rawURL))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			links, err := f.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(f.Len(), len(tt.givenLinks)) {
+				t.Error(cmp.Diff(f.Len(), len(tt.givenLinks)))
+			}
+
+			restored := New()
+
+			for _, link := range links {
+				if err := restored.Push(link); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []string
+
+			for restored.Len() > 0 {
+				link, err := restored.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
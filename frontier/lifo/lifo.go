@@ -0,0 +1,62 @@
+package lifo
+
+import (
+	"errors"
+
+	"github.com/clarke94/crawler/page"
+)
+
+var (
+	// ErrInvalidLink is returned when a nil link is pushed.
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrEmpty is returned when popping from an empty LIFO.
+	ErrEmpty = errors.New("frontier is empty")
+)
+
+// LIFO is a Frontier that pops the most recently pushed link first, crawling depth first.
+type LIFO struct {
+	links []*page.Link
+}
+
+// New initializes a new LIFO Frontier.
+func New() *LIFO {
+	return &LIFO{}
+}
+
+// Push adds the link to the top of the stack.
+func (l *LIFO) Push(link *page.Link) error {
+	if link == nil {
+		return ErrInvalidLink
+	}
+
+	l.links = append(l.links, link)
+
+	return nil
+}
+
+// Pop removes and returns the link at the top of the stack.
+func (l *LIFO) Pop() (*page.Link, error) {
+	n := len(l.links)
+	if n == 0 {
+		return nil, ErrEmpty
+	}
+
+	link := l.links[n-1]
+	l.links[n-1] = nil
+	l.links = l.links[:n-1]
+
+	return link, nil
+}
+
+// Len returns the number of links in the stack.
+func (l *LIFO) Len() int {
+	return len(l.links)
+}
+
+// Snapshot returns a copy of the links in the order they were pushed.
+func (l *LIFO) Snapshot() ([]*page.Link, error) {
+	links := make([]*page.Link, len(l.links))
+	copy(links, l.links)
+
+	return links, nil
+}
@@ -0,0 +1,131 @@
+package priority
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+
+	"github.com/clarke94/crawler/page"
+)
+
+var (
+	// ErrInvalidLink is returned when a nil link is pushed.
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrEmpty is returned when popping from an empty Priority.
+	ErrEmpty = errors.New("frontier is empty")
+)
+
+// ScoreFunc scores a link, links with a higher score are crawled first.
+type ScoreFunc func(link *page.Link) float64
+
+// Priority is a Frontier that pops the link with the highest score first,
+// links with an equal score are popped in the order they were pushed.
+type Priority struct {
+	score ScoreFunc
+	items *items
+	seq   uint64
+}
+
+// New initializes a new Priority Frontier with the given score function.
+func New(score ScoreFunc) *Priority {
+	return &Priority{
+		score: score,
+		items: &items{},
+	}
+}
+
+// ShallowFirst is a ScoreFunc that scores links with a lower depth higher.
+func ShallowFirst(link *page.Link) float64 {
+	return -float64(link.Depth)
+}
+
+// Push scores the link and adds it to the queue.
+func (p *Priority) Push(link *page.Link) error {
+	if link == nil {
+		return ErrInvalidLink
+	}
+
+	p.seq++
+
+	heap.Push(p.items, &item{
+		link:  link,
+		score: p.score(link),
+		seq:   p.seq,
+	})
+
+	return nil
+}
+
+// Pop removes and returns the link with the highest score.
+func (p *Priority) Pop() (*page.Link, error) {
+	if p.items.Len() == 0 {
+		return nil, ErrEmpty
+	}
+
+	i, _ := heap.Pop(p.items).(*item)
+
+	return i.link, nil
+}
+
+// Len returns the number of links in the queue.
+func (p *Priority) Len() int {
+	return p.items.Len()
+}
+
+// Snapshot returns a copy of the links in the order they were pushed.
+func (p *Priority) Snapshot() ([]*page.Link, error) {
+	sorted := make(items, len(*p.items))
+	copy(sorted, *p.items)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].seq < sorted[j].seq
+	})
+
+	links := make([]*page.Link, 0, len(sorted))
+
+	for _, i := range sorted {
+		links = append(links, i.link)
+	}
+
+	return links, nil
+}
+
+type item struct {
+	link  *page.Link
+	score float64
+	seq   uint64
+}
+
+// items implements heap.Interface ordered by highest score then lowest sequence.
+type items []*item
+
+func (h items) Len() int {
+	return len(h)
+}
+
+func (h items) Less(i, j int) bool {
+	if h[i].score == h[j].score {
+		return h[i].seq < h[j].seq
+	}
+
+	return h[i].score > h[j].score
+}
+
+func (h items) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *items) Push(x interface{}) {
+	i, _ := x.(*item)
+	*h = append(*h, i)
+}
+
+func (h *items) Pop() interface{} {
+	old := *h
+	n := len(old)
+	i := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return i
+}
@@ -0,0 +1,194 @@
+package priority
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPriority_Pop(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenScore ScoreFunc
+		givenLinks []*page.Link
+		want       []string
+	}{
+		{
+			name:       "expect shallow links first given shallow first",
+			givenScore: ShallowFirst,
+			givenLinks: []*page.Link{
+				{URL: testutil.URLMustParse("http://localhost/a"), Depth: 2},
+				{URL: testutil.URLMustParse("http://localhost/b"), Depth: 0},
+				{URL: testutil.URLMustParse("http://localhost/c"), Depth: 1},
+			},
+			want: []string{"http://localhost/b", "http://localhost/c", "http://localhost/a"},
+		},
+		{
+			name: "expect links with equal scores in the order they were pushed",
+			givenScore: func(_ *page.Link) float64 {
+				return 1
+			},
+			givenLinks: []*page.Link{
+				{URL: testutil.URLMustParse("http://localhost/a")},
+				{URL: testutil.URLMustParse("http://localhost/b")},
+				{URL: testutil.URLMustParse("http://localhost/c")},
+			},
+			want: []string{"http://localhost/a", "http://localhost/b", "http://localhost/c"},
+		},
+		{
+			name: "expect highest score first given a custom score",
+			givenScore: func(link *page.Link) float64 {
+				if strings.HasPrefix(link.URL.Path, "/products") {
+					return 1
+				}
+
+				return 0
+			},
+			givenLinks: []*page.Link{
+				{URL: testutil.URLMustParse("http://localhost/about")},
+				{URL: testutil.URLMustParse("http://localhost/products/1")},
+				{URL: testutil.URLMustParse("http://localhost/contact")},
+				{URL: testutil.URLMustParse("http://localhost/products/2")},
+			},
+			want: []string{
+				"http://localhost/products/1",
+				"http://localhost/products/2",
+				"http://localhost/about",
+				"http://localhost/contact",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.givenScore)
+
+			for _, link := range tt.givenLinks {
+				if err := p.Push(link); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []string
+
+			for p.Len() > 0 {
+				link, err := p.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+
+			_, err := p.Pop()
+			if !cmp.Equal(err, ErrEmpty, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrEmpty, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestPriority_Push(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *page.Link
+		wantLen   int
+		wantErr   error
+	}{
+		{
+			name:      "expect link pushed",
+			givenLink: page.NewLink(testutil.URLMustParse("http://localhost")),
+			wantLen:   1,
+			wantErr:   nil,
+		},
+		{
+			name:      "expect error given a nil link",
+			givenLink: nil,
+			wantLen:   0,
+			wantErr:   ErrInvalidLink,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(ShallowFirst)
+
+			err := p.Push(tt.givenLink)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(p.Len(), tt.wantLen) {
+				t.Error(cmp.Diff(p.Len(), tt.wantLen))
+			}
+		})
+	}
+}
+
+func TestPriority_Snapshot(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenLinks []string
+		want       []string
+	}{
+		{
+			name:       "expect links restored in the same order given a snapshot pushed to a new Priority",
+			givenLinks: []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+			want:       []string{"http://localhost/1", "http://localhost/2", "http://localhost/3"},
+		},
+		{
+			name:       "expect no links given nothing pushed",
+			givenLinks: nil,
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(func(*page.Link) float64 { return 0 })
+
+			for _, rawURL := range tt.givenLinks {
+				if err := f.Push(page.NewLink(testutil.URLMustParse(rawURL))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			links, err := f.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(f.Len(), len(tt.givenLinks)) {
+				t.Error(cmp.Diff(f.Len(), len(tt.givenLinks)))
+			}
+
+			restored := New(func(*page.Link) float64 { return 0 })
+
+			for _, link := range links {
+				if err := restored.Push(link); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []string
+
+			for restored.Len() > 0 {
+				link, err := restored.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, link.URL.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package disk
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	filePerm = 0o600
+	dirPerm  = 0o700
+)
+
+// entry is a cookie saved to the file with the URL that set it.
+type entry struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// Disk is a http.CookieJar that keeps the cookies of each host in memory and saves
+// them to a file, so a session is kept between crawls and between runs.
+//
+// The cookies in the file are loaded by New, Save writes the cookies that have
+// not expired back to the file.
+type Disk struct {
+	path    string
+	jar     *cookiejar.Jar
+	mu      *sync.Mutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+// New initializes a new Disk jar with the cookies saved in the file at the path, if it exists.
+func New(path string) (*Disk, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Disk{
+		path:    path,
+		jar:     jar,
+		mu:      &sync.Mutex{},
+		entries: map[string]entry{},
+		now:     time.Now,
+	}
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// SetCookies implements http.CookieJar.
+func (d *Disk) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.set(u, cookies)
+}
+
+// Cookies implements http.CookieJar.
+func (d *Disk) Cookies(u *url.URL) []*http.Cookie {
+	return d.jar.Cookies(u)
+}
+
+// Save writes the cookies that have not expired to the file, it is written
+// to a temporary file first so the file is never partially written.
+func (d *Disk) Save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entries := make([]entry, 0, len(d.entries))
+
+	for _, e := range d.entries {
+		if !d.expired(e.Cookie) {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return key(entries[i].URL, entries[i].Cookie) < key(entries[j].URL, entries[j].Cookie)
+	})
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.path), dirPerm); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(d.path+".tmp", b, filePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(d.path+".tmp", d.path)
+}
+
+// load sets the cookies saved in the file, a missing file has no cookies.
+func (d *Disk) load() error {
+	b, err := ioutil.ReadFile(d.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var entries []entry
+
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			return err
+		}
+
+		if !d.expired(e.Cookie) {
+			d.set(u, []*http.Cookie{e.Cookie})
+		}
+	}
+
+	return nil
+}
+
+func (d *Disk) set(u *url.URL, cookies []*http.Cookie) {
+	d.jar.SetCookies(u, cookies)
+
+	for _, c := range cookies {
+		saved := *c
+
+		// A max age is saved as an expiry so the cookie expires across runs.
+		if saved.MaxAge > 0 {
+			saved.Expires = d.now().Add(time.Duration(saved.MaxAge) * time.Second)
+			saved.MaxAge = 0
+		}
+
+		k := key(u.String(), &saved)
+
+		if d.expired(&saved) {
+			delete(d.entries, k)
+
+			continue
+		}
+
+		d.entries[k] = entry{URL: u.String(), Cookie: &saved}
+	}
+}
+
+// expired reports whether the cookie is deleted or past its expiry.
+func (d *Disk) expired(c *http.Cookie) bool {
+	return c.MaxAge < 0 || (!c.Expires.IsZero() && !c.Expires.After(d.now()))
+}
+
+// key identifies a cookie by its domain, or the host of the URL that set it, its path and name.
+func key(rawURL string, c *http.Cookie) string {
+	domain := c.Domain
+	if domain == "" {
+		if u, err := url.Parse(rawURL); err == nil {
+			domain = u.Hostname()
+		}
+	}
+
+	return domain + ";" + c.Path + ";" + c.Name
+}
@@ -0,0 +1,126 @@
+package disk
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenFile []byte
+		wantErr   bool
+	}{
+		{
+			name:      "expect Disk jar to initialize given no file",
+			givenFile: nil,
+			wantErr:   false,
+		},
+		{
+			name:      "expect Disk jar to initialize given saved cookies",
+			givenFile: []byte(`[{"url":"http://localhost","cookie":{"Name":"session","Value":"1"}}]`),
+			wantErr:   false,
+		},
+		{
+			name:      "expect error given an invalid file",
+			givenFile: []byte(`{`),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cookies.json")
+
+			if tt.givenFile != nil {
+				if err := ioutil.WriteFile(path, tt.givenFile, filePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := New(path)
+			if !cmp.Equal(err != nil, tt.wantErr) {
+				t.Error(cmp.Diff(err != nil, tt.wantErr))
+			}
+		})
+	}
+}
+
+func TestDisk_Save(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenCookies []*http.Cookie
+		want         []string
+	}{
+		{
+			name:         "expect session cookie kept",
+			givenCookies: []*http.Cookie{{Name: "session", Value: "1"}},
+			want:         []string{"session=1"},
+		},
+		{
+			name:         "expect cookie with a max age kept",
+			givenCookies: []*http.Cookie{{Name: "session", Value: "1", MaxAge: 60}},
+			want:         []string{"session=1"},
+		},
+		{
+			name:         "expect expired cookie dropped",
+			givenCookies: []*http.Cookie{{Name: "session", Value: "1", Expires: time.Now().Add(-time.Hour)}},
+			want:         []string{},
+		},
+		{
+			name: "expect deleted cookie dropped",
+			givenCookies: []*http.Cookie{
+				{Name: "session", Value: "1"},
+				{Name: "session", Value: "", MaxAge: -1},
+			},
+			want: []string{},
+		},
+		{
+			name: "expect latest value of a cookie kept",
+			givenCookies: []*http.Cookie{
+				{Name: "session", Value: "1"},
+				{Name: "session", Value: "2"},
+			},
+			want: []string{"session=2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cookies", "cookies.json")
+			u := testutil.URLMustParse("http://localhost")
+
+			d, err := New(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, c := range tt.givenCookies {
+				d.SetCookies(u, []*http.Cookie{c})
+			}
+
+			if err := d.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			reopened, err := New(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+
+			for _, c := range reopened.Cookies(u) {
+				got = append(got, c.String())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
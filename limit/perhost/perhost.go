@@ -0,0 +1,213 @@
+package perhost
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// limits are the limits applied to the requests of a host.
+type limits struct {
+	rate   float64
+	burst  int
+	delay  time.Duration
+	jitter time.Duration
+}
+
+// bucket is the state of the requests to a host.
+type bucket struct {
+	tokens float64
+	filled time.Time
+	next   time.Time
+}
+
+// PerHost is a Limiter that throttles the requests to each host with a token bucket
+// and a minimum delay between requests. Requests to different hosts do not wait on
+// each other. A PerHost without options does not throttle requests.
+//
+// Ready reports when a host can be sent a request, so the crawler defers
+// the links of a host that is not ready rather than waiting for it.
+type PerHost struct {
+	limits      limits
+	hosts       map[string]limits
+	hostOptions map[string][]Option
+
+	mu      *sync.Mutex
+	buckets map[string]*bucket
+	rand    *rand.Rand
+	now     func() time.Time
+}
+
+// Option is a functional option to modify the default PerHost instance.
+type Option func(p *PerHost)
+
+// WithRate allows perSecond requests to a host each second on average, with up to
+// burst requests sent at once. A rate of zero or less does not limit the rate and
+// a burst less than one is a burst of one.
+func WithRate(perSecond float64, burst int) Option {
+	return func(p *PerHost) {
+		if burst < 1 {
+			burst = 1
+		}
+
+		p.limits.rate = perSecond
+		p.limits.burst = burst
+	}
+}
+
+// WithDelay waits at least min between the requests to a host, plus a random
+// duration up to jitter so requests do not arrive at a fixed interval.
+func WithDelay(min, jitter time.Duration) Option {
+	return func(p *PerHost) {
+		p.limits.delay = min
+		p.limits.jitter = jitter
+	}
+}
+
+// WithHost overrides the limits for a single host, the options are
+// applied on top of the limits for every other host.
+func WithHost(host string, options ...Option) Option {
+	return func(p *PerHost) {
+		p.hostOptions[strings.ToLower(host)] = options
+	}
+}
+
+// New initializes a new PerHost Limiter.
+func New(options ...Option) *PerHost {
+	p := &PerHost{
+		limits:      limits{burst: 1},
+		hosts:       map[string]limits{},
+		hostOptions: map[string][]Option{},
+		mu:          &sync.Mutex{},
+		buckets:     map[string]*bucket{},
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:         time.Now,
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	for host, hostOptions := range p.hostOptions {
+		h := &PerHost{limits: p.limits, hostOptions: map[string][]Option{}}
+
+		for _, opt := range hostOptions {
+			opt(h)
+		}
+
+		p.hosts[host] = h.limits
+	}
+
+	return p
+}
+
+// Wait blocks until a request can be sent to the host of the URL,
+// or returns the error of the context if it is done first.
+func (p *PerHost) Wait(ctx context.Context, u *url.URL) error {
+	wait := p.reserve(strings.ToLower(u.Hostname()))
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ready returns the time a request can be sent to the host of the URL without waiting,
+// or the zero time if it can be sent now.
+func (p *PerHost) Ready(u *url.URL) time.Time {
+	host := strings.ToLower(u.Hostname())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	b, ok := p.buckets[host]
+	if !ok {
+		return time.Time{}
+	}
+
+	l := p.limitsFor(host)
+	at := now
+
+	if l.rate > 0 {
+		if tokens := math.Min(float64(l.burst), b.tokens+now.Sub(b.filled).Seconds()*l.rate); tokens < 1 {
+			at = now.Add(time.Duration((1 - tokens) / l.rate * float64(time.Second)))
+		}
+	}
+
+	if b.next.After(at) {
+		at = b.next
+	}
+
+	if !at.After(now) {
+		return time.Time{}
+	}
+
+	return at
+}
+
+// limitsFor returns the limits of the host.
+func (p *PerHost) limitsFor(host string) limits {
+	if l, ok := p.hosts[host]; ok {
+		return l
+	}
+
+	return p.limits
+}
+
+// reserve takes a token from the bucket of the host and returns how long
+// to wait before the request is sent. The delay before the next request,
+// including its jitter, is decided when a request is reserved.
+func (p *PerHost) reserve(host string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l := p.limitsFor(host)
+	now := p.now()
+
+	b, ok := p.buckets[host]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), filled: now}
+		p.buckets[host] = b
+	}
+
+	at := now
+
+	if l.rate > 0 {
+		b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.filled).Seconds()*l.rate)
+		b.filled = now
+		b.tokens--
+
+		if b.tokens < 0 {
+			at = now.Add(time.Duration(-b.tokens / l.rate * float64(time.Second)))
+		}
+	}
+
+	if b.next.After(at) {
+		at = b.next
+	}
+
+	if l.delay+l.jitter > 0 {
+		delay := l.delay
+		if l.jitter > 0 {
+			delay += time.Duration(p.rand.Int63n(int64(l.jitter)))
+		}
+
+		b.next = at.Add(delay)
+	}
+
+	return at.Sub(now)
+}
@@ -0,0 +1,200 @@
+package perhost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPerHost_reserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenOptions []Option
+		givenHosts   []string
+		want         []time.Duration
+	}{
+		{
+			name:         "expect no wait given no options",
+			givenOptions: nil,
+			givenHosts:   []string{"a", "a", "a"},
+			want:         []time.Duration{0, 0, 0},
+		},
+		{
+			name:         "expect requests spaced by the rate given a burst of one",
+			givenOptions: []Option{WithRate(10, 1)},
+			givenHosts:   []string{"a", "a", "a"},
+			want:         []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "expect burst requests sent at once",
+			givenOptions: []Option{WithRate(10, 2)},
+			givenHosts:   []string{"a", "a", "a"},
+			want:         []time.Duration{0, 0, 100 * time.Millisecond},
+		},
+		{
+			name:         "expect hosts limited separately",
+			givenOptions: []Option{WithRate(10, 1)},
+			givenHosts:   []string{"a", "b", "a", "b"},
+			want:         []time.Duration{0, 0, 100 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name:         "expect minimum delay between requests",
+			givenOptions: []Option{WithDelay(time.Second, 0)},
+			givenHosts:   []string{"a", "a", "a"},
+			want:         []time.Duration{0, time.Second, 2 * time.Second},
+		},
+		{
+			name:         "expect host limits given a host override",
+			givenOptions: []Option{WithRate(10, 1), WithHost("B", WithRate(1, 1))},
+			givenHosts:   []string{"a", "a", "b", "b"},
+			want:         []time.Duration{0, 100 * time.Millisecond, 0, time.Second},
+		},
+		{
+			name:         "expect host override applied on top of the limits given after it",
+			givenOptions: []Option{WithHost("b", WithDelay(time.Second, 0)), WithRate(10, 1)},
+			givenHosts:   []string{"b", "b", "b"},
+			want:         []time.Duration{0, time.Second, 2 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.givenOptions...)
+
+			now := time.Now()
+			p.now = func() time.Time {
+				return now
+			}
+
+			var got []time.Duration
+
+			for _, host := range tt.givenHosts {
+				got = append(got, p.reserve(host))
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestPerHost_reserve_Jitter(t *testing.T) {
+	p := New(WithDelay(100*time.Millisecond, 50*time.Millisecond))
+
+	now := time.Now()
+	p.now = func() time.Time {
+		return now
+	}
+
+	_ = p.reserve("a")
+
+	for i := 1; i <= 10; i++ {
+		got := p.reserve("a")
+		if got < time.Duration(i)*100*time.Millisecond || got >= time.Duration(i)*150*time.Millisecond {
+			t.Errorf("expected wait between %v and %v, got %v", time.Duration(i)*100*time.Millisecond, time.Duration(i)*150*time.Millisecond, got)
+		}
+	}
+}
+
+func TestPerHost_Ready(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenOptions  []Option
+		givenReserved []string
+		givenURL      string
+		want          time.Duration
+	}{
+		{
+			name:          "expect ready now given no requests to the host",
+			givenOptions:  []Option{WithRate(1, 1)},
+			givenReserved: []string{"b"},
+			givenURL:      "http://a",
+			want:          0,
+		},
+		{
+			name:          "expect ready now given a token left in the burst",
+			givenOptions:  []Option{WithRate(10, 2)},
+			givenReserved: []string{"a"},
+			givenURL:      "http://a",
+			want:          0,
+		},
+		{
+			name:          "expect ready once a token is added given an empty bucket",
+			givenOptions:  []Option{WithRate(10, 1)},
+			givenReserved: []string{"a", "a"},
+			givenURL:      "http://A",
+			want:          200 * time.Millisecond,
+		},
+		{
+			name:          "expect ready after the minimum delay",
+			givenOptions:  []Option{WithDelay(time.Second, 0)},
+			givenReserved: []string{"a"},
+			givenURL:      "http://a",
+			want:          time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.givenOptions...)
+
+			now := time.Now()
+			p.now = func() time.Time {
+				return now
+			}
+
+			for _, host := range tt.givenReserved {
+				_ = p.reserve(host)
+			}
+
+			var got time.Duration
+			if ready := p.Ready(testutil.URLMustParse(tt.givenURL)); !ready.IsZero() {
+				got = ready.Sub(now)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestPerHost_Wait(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		givenOptions []Option
+		givenCtx     context.Context
+		wantErr      []error
+	}{
+		{
+			name:         "expect no error given requests within the limits",
+			givenOptions: []Option{WithRate(1000, 1)},
+			givenCtx:     context.Background(),
+			wantErr:      []error{nil, nil},
+		},
+		{
+			name:         "expect context error given a cancelled context",
+			givenOptions: []Option{WithRate(0.001, 1)},
+			givenCtx:     canceled,
+			wantErr:      []error{context.Canceled, context.Canceled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.givenOptions...)
+
+			for _, want := range tt.wantErr {
+				err := p.Wait(tt.givenCtx, testutil.URLMustParse("http://localhost"))
+				if !cmp.Equal(err, want, cmpopts.EquateErrors()) {
+					t.Error(cmp.Diff(err, want, cmpopts.EquateErrors()))
+				}
+			}
+		})
+	}
+}
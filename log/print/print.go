@@ -3,6 +3,8 @@ package print
 import (
 	"fmt"
 	"net/url"
+
+	"github.com/clarke94/crawler/page"
 )
 
 // Print is a Logger that prints with the standard format package.
@@ -14,6 +16,7 @@ func (p *Print) Error(err error) {
 }
 
 // Info prints the given parameters to the console.
-func (p *Print) Info(visited *url.URL, found []*url.URL) {
-	fmt.Printf("Visited %s and found %v \n", visited.String(), found)
+func (p *Print) Info(visited *page.Link, resp *page.Response, found []*url.URL) {
+	fmt.Printf("Visited %s at depth %d with status %d in %v and found %v \n",
+		visited.URL.String(), visited.Depth, resp.StatusCode, resp.Duration, found)
 }
@@ -2,10 +2,12 @@ package print
 
 import (
 	"errors"
+	"net/http"
 	"net/url"
 	"testing"
 
 	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
 )
 
 func TestPrint_Error(t *testing.T) {
@@ -30,12 +32,12 @@ func TestPrint_Error(t *testing.T) {
 func TestPrint_Info(t *testing.T) {
 	tests := []struct {
 		name         string
-		givenVisited url.URL
+		givenVisited *page.Link
 		givenFound   []*url.URL
 	}{
 		{
 			name:         "expect log to print without panic",
-			givenVisited: *testutil.URLMustParse("http://localhost"),
+			givenVisited: page.NewLink(testutil.URLMustParse("http://localhost")),
 			givenFound:   []*url.URL{},
 		},
 	}
@@ -43,7 +45,7 @@ func TestPrint_Info(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Print{}
 
-			p.Info(&tt.givenVisited, tt.givenFound)
+			p.Info(tt.givenVisited, &page.Response{StatusCode: http.StatusOK}, tt.givenFound)
 		})
 	}
 }
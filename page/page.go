@@ -0,0 +1,197 @@
+package page
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Link is a URL queued for crawling along with where it was found.
+type Link struct {
+	// URL is the location to crawl.
+	URL *url.URL
+	// Parent is the URL of the page the link was found on, nil for a seed.
+	Parent *url.URL
+	// Depth is the number of links followed from the seed, zero for a seed.
+	Depth int
+	// Scope is the set of hosts of the seeds the link was found from.
+	Scope Scope
+	// Attempt is the number of times the link has been retried, zero for the first request.
+	Attempt int
+}
+
+// NewLink initializes a new seed Link for the given URL.
+func NewLink(u *url.URL) *Link {
+	return &Link{
+		URL: u,
+	}
+}
+
+// Child returns the Link for a URL found on the page of l.
+func (l *Link) Child(u *url.URL) *Link {
+	return &Link{
+		URL:    u,
+		Parent: l.URL,
+		Depth:  l.Depth + 1,
+		Scope:  l.Scope,
+	}
+}
+
+// Retry returns a copy of l for the next attempt to crawl it.
+func (l *Link) Retry() *Link {
+	retry := *l
+	retry.Attempt++
+
+	return &retry
+}
+
+// linkJSON is the JSON encoding of a Link.
+type linkJSON struct {
+	URL     string   `json:"url"`
+	Parent  string   `json:"parent,omitempty"`
+	Depth   int      `json:"depth,omitempty"`
+	Scope   []string `json:"scope,omitempty"`
+	Attempt int      `json:"attempt,omitempty"`
+}
+
+// MarshalJSON encodes the link as JSON so it can be persisted.
+func (l *Link) MarshalJSON() ([]byte, error) {
+	v := linkJSON{
+		URL:     l.URL.String(),
+		Depth:   l.Depth,
+		Scope:   l.Scope.Hosts(),
+		Attempt: l.Attempt,
+	}
+
+	if l.Parent != nil {
+		v.Parent = l.Parent.String()
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a link encoded with MarshalJSON.
+func (l *Link) UnmarshalJSON(b []byte) error {
+	var v linkJSON
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(v.URL)
+	if err != nil {
+		return err
+	}
+
+	*l = Link{
+		URL:     u,
+		Depth:   v.Depth,
+		Attempt: v.Attempt,
+	}
+
+	if v.Parent != "" {
+		if l.Parent, err = url.Parse(v.Parent); err != nil {
+			return err
+		}
+	}
+
+	if v.Scope != nil {
+		l.Scope = Scope{}
+
+		for _, host := range v.Scope {
+			l.Scope[host] = true
+		}
+	}
+
+	return nil
+}
+
+// Scope is the set of hosts a crawl is restricted to, derived from its seed URLs.
+type Scope map[string]bool
+
+// NewScope initializes a new Scope with the hosts of the given seed URLs.
+func NewScope(seeds ...*url.URL) Scope {
+	s := Scope{}
+
+	for _, u := range seeds {
+		s[u.Hostname()] = true
+	}
+
+	return s
+}
+
+// Hosts returns the sorted hosts in the scope, nil for a nil Scope.
+func (s Scope) Hosts() []string {
+	if s == nil {
+		return nil
+	}
+
+	hosts := make([]string, 0, len(s))
+
+	for host := range s {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+
+	return hosts
+}
+
+// Contains reports whether the host of the URL is in the scope,
+// a nil Scope contains every URL.
+func (s Scope) Contains(u *url.URL) bool {
+	if s == nil {
+		return true
+	}
+
+	return s[u.Hostname()]
+}
+
+// scopeKey is the context key of the Scope of a request.
+type scopeKey struct{}
+
+// ContextWithScope returns a copy of the context that carries the scope of the link
+// being requested, so a Requester can follow redirects within the scope.
+func ContextWithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// ScopeFromContext returns the Scope carried by the context, nil if it carries none.
+func ScopeFromContext(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeKey{}).(Scope)
+
+	return s
+}
+
+// Response is the response received for a crawled Link.
+type Response struct {
+	// Request is the request that was sent for the Link.
+	Request *http.Request
+	// URL is the final URL of the response after any redirects.
+	URL *url.URL
+	// Redirects is the URLs redirected from in order, starting with the URL of the Request.
+	// It is empty if the request was not redirected.
+	Redirects []*url.URL
+	// StatusCode is the HTTP status code of the response, e.g. 200.
+	StatusCode int
+	// Header is the HTTP header of the response.
+	Header http.Header
+	// Duration is the time taken to receive the response.
+	Duration time.Duration
+	// Body is the body of the response, the Scraper is responsible for closing it.
+	Body io.ReadCloser
+}
+
+// OK reports whether the response has a 2xx status code.
+func (r *Response) OK() bool {
+	return r.StatusCode >= http.StatusOK && r.StatusCode < http.StatusMultipleChoices
+}
+
+// ContentType returns the Content-Type header of the response.
+func (r *Response) ContentType() string {
+	return r.Header.Get("Content-Type")
+}
@@ -0,0 +1,373 @@
+package page
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		givenURL *url.URL
+		want     *Link
+	}{
+		{
+			name:     "expect seed link with no parent and zero depth",
+			givenURL: testutil.URLMustParse("http://localhost"),
+			want: &Link{
+				URL: testutil.URLMustParse("http://localhost"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewLink(tt.givenURL)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestLink_Child(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *Link
+		givenURL  *url.URL
+		want      *Link
+	}{
+		{
+			name:      "expect child of seed to have depth one",
+			givenLink: NewLink(testutil.URLMustParse("http://localhost")),
+			givenURL:  testutil.URLMustParse("http://localhost/foo"),
+			want: &Link{
+				URL:    testutil.URLMustParse("http://localhost/foo"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  1,
+			},
+		},
+		{
+			name: "expect child depth to increment parent depth",
+			givenLink: &Link{
+				URL:    testutil.URLMustParse("http://localhost/foo"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  3,
+			},
+			givenURL: testutil.URLMustParse("http://localhost/bar"),
+			want: &Link{
+				URL:    testutil.URLMustParse("http://localhost/bar"),
+				Parent: testutil.URLMustParse("http://localhost/foo"),
+				Depth:  4,
+			},
+		},
+		{
+			name: "expect child to keep the parent scope",
+			givenLink: &Link{
+				URL:   testutil.URLMustParse("http://localhost"),
+				Scope: Scope{"localhost": true},
+			},
+			givenURL: testutil.URLMustParse("http://localhost/bar"),
+			want: &Link{
+				URL:    testutil.URLMustParse("http://localhost/bar"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  1,
+				Scope:  Scope{"localhost": true},
+			},
+		},
+		{
+			name: "expect child of a retried link to be a first attempt",
+			givenLink: &Link{
+				URL:     testutil.URLMustParse("http://localhost"),
+				Attempt: 2,
+			},
+			givenURL: testutil.URLMustParse("http://localhost/bar"),
+			want: &Link{
+				URL:    testutil.URLMustParse("http://localhost/bar"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.givenLink.Child(tt.givenURL)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestLink_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *Link
+		want      *Link
+	}{
+		{
+			name: "expect retry to increment the attempt",
+			givenLink: &Link{
+				URL:     testutil.URLMustParse("http://localhost/foo"),
+				Parent:  testutil.URLMustParse("http://localhost"),
+				Depth:   1,
+				Scope:   Scope{"localhost": true},
+				Attempt: 1,
+			},
+			want: &Link{
+				URL:     testutil.URLMustParse("http://localhost/foo"),
+				Parent:  testutil.URLMustParse("http://localhost"),
+				Depth:   1,
+				Scope:   Scope{"localhost": true},
+				Attempt: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.givenLink.Retry()
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+
+			if got == tt.givenLink {
+				t.Error("expected retry to return a copy of the link")
+			}
+		})
+	}
+}
+
+func TestLink_JSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenLink *Link
+		wantJSON  string
+	}{
+		{
+			name:      "expect seed link to round trip",
+			givenLink: NewLink(testutil.URLMustParse("http://localhost")),
+			wantJSON:  `{"url":"http://localhost"}`,
+		},
+		{
+			name: "expect found link with scope to round trip",
+			givenLink: &Link{
+				URL:    testutil.URLMustParse("http://localhost/foo?bar=baz"),
+				Parent: testutil.URLMustParse("http://localhost"),
+				Depth:  2,
+				Scope:  Scope{"localhost": true, "example.com": true},
+			},
+			wantJSON: `{"url":"http://localhost/foo?bar=baz","parent":"http://localhost","depth":2,"scope":["example.com","localhost"]}`,
+		},
+		{
+			name: "expect retried link to round trip",
+			givenLink: &Link{
+				URL:     testutil.URLMustParse("http://localhost"),
+				Attempt: 2,
+			},
+			wantJSON: `{"url":"http://localhost","attempt":2}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.givenLink)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(string(b), tt.wantJSON) {
+				t.Error(cmp.Diff(string(b), tt.wantJSON))
+			}
+
+			got := &Link{}
+
+			if err := json.Unmarshal(b, got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(got, tt.givenLink) {
+				t.Error(cmp.Diff(got, tt.givenLink))
+			}
+		})
+	}
+}
+
+func TestNewScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenSeeds []*url.URL
+		want       Scope
+	}{
+		{
+			name: "expect the host of every seed",
+			givenSeeds: []*url.URL{
+				testutil.URLMustParse("http://localhost:8080/foo"),
+				testutil.URLMustParse("https://example.com"),
+				testutil.URLMustParse("https://example.com/bar"),
+			},
+			want: Scope{
+				"localhost":   true,
+				"example.com": true,
+			},
+		},
+		{
+			name:       "expect empty scope given no seeds",
+			givenSeeds: nil,
+			want:       Scope{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewScope(tt.givenSeeds...)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestScope_Contains(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenScope Scope
+		givenURL   *url.URL
+		want       bool
+	}{
+		{
+			name:       "expect true given a URL with a seed host",
+			givenScope: Scope{"example.com": true, "localhost": true},
+			givenURL:   testutil.URLMustParse("http://localhost/foo"),
+			want:       true,
+		},
+		{
+			name:       "expect true given a URL with a seed host on another port",
+			givenScope: Scope{"localhost": true},
+			givenURL:   testutil.URLMustParse("http://localhost:8080/foo"),
+			want:       true,
+		},
+		{
+			name:       "expect false given a URL with a different host",
+			givenScope: Scope{"example.com": true},
+			givenURL:   testutil.URLMustParse("http://sub.example.com"),
+			want:       false,
+		},
+		{
+			name:       "expect true given a nil scope",
+			givenScope: nil,
+			givenURL:   testutil.URLMustParse("http://example.com"),
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.givenScope.Contains(tt.givenURL)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestScopeFromContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		givenCtx context.Context
+		want     Scope
+	}{
+		{
+			name:     "expect the scope given a context with a scope",
+			givenCtx: ContextWithScope(context.Background(), Scope{"localhost": true}),
+			want:     Scope{"localhost": true},
+		},
+		{
+			name:     "expect nil given a context without a scope",
+			givenCtx: context.Background(),
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScopeFromContext(tt.givenCtx)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestResponse_OK(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenStatusCode int
+		want            bool
+	}{
+		{
+			name:            "expect OK given 200",
+			givenStatusCode: http.StatusOK,
+			want:            true,
+		},
+		{
+			name:            "expect OK given 204",
+			givenStatusCode: http.StatusNoContent,
+			want:            true,
+		},
+		{
+			name:            "expect not OK given 301",
+			givenStatusCode: http.StatusMovedPermanently,
+			want:            false,
+		},
+		{
+			name:            "expect not OK given 404",
+			givenStatusCode: http.StatusNotFound,
+			want:            false,
+		},
+		{
+			name:            "expect not OK given 500",
+			givenStatusCode: http.StatusInternalServerError,
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Response{StatusCode: tt.givenStatusCode}
+
+			got := r.OK()
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestResponse_ContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenHeader http.Header
+		want        string
+	}{
+		{
+			name:        "expect content type given header",
+			givenHeader: http.Header{"Content-Type": []string{"text/html"}},
+			want:        "text/html",
+		},
+		{
+			name:        "expect empty content type given no header",
+			givenHeader: nil,
+			want:        "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Response{Header: tt.givenHeader}
+
+			got := r.ContentType()
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package crawler
+
+import (
+	"context"
+	"sync"
+)
+
+// gate holds workers back from crawling while it is closed.
+type gate struct {
+	mu     *sync.Mutex
+	closed bool
+	opened chan struct{}
+}
+
+// newGate initializes a new open gate.
+func newGate() *gate {
+	return &gate{
+		mu: &sync.Mutex{},
+	}
+}
+
+// close holds back workers that wait on the gate until it is opened.
+func (g *gate) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return
+	}
+
+	g.closed = true
+	g.opened = make(chan struct{})
+}
+
+// open releases the workers waiting on the gate.
+func (g *gate) open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.closed {
+		return
+	}
+
+	g.closed = false
+	close(g.opened)
+}
+
+// isClosed reports whether the gate is closed.
+func (g *gate) isClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.closed
+}
+
+// wait blocks until the gate is open or the context is done.
+func (g *gate) wait(ctx context.Context) {
+	g.mu.Lock()
+	if !g.closed {
+		g.mu.Unlock()
+
+		return
+	}
+
+	opened := g.opened
+	g.mu.Unlock()
+
+	select {
+	case <-opened:
+	case <-ctx.Done():
+	}
+}
+
+// Pause stops the Crawler sending new requests, the requests in flight are
+// left to finish and found URLs are queued. Links waiting to be crawled are
+// kept until Resume is called, cancelling the context of a paused crawl still
+// stops it. A Crawler paused before a crawl starts does not send any requests
+// until it is resumed.
+func (c *Crawler) Pause() {
+	c.gate.close()
+}
+
+// Resume continues the crawls of a paused Crawler.
+func (c *Crawler) Resume() {
+	c.gate.open()
+}
+
+// Paused reports whether the Crawler is paused.
+func (c *Crawler) Paused() bool {
+	return c.gate.isClosed()
+}
@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCrawler_Pause(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	tests := []struct {
+		name        string
+		givenCancel bool
+		want        *Report
+		wantErr     error
+	}{
+		{
+			name:        "expect crawl to finish once resumed",
+			givenCancel: false,
+			want: &Report{Visited: []*url.URL{
+				testutil.URLMustParse("http://localhost"),
+				testutil.URLMustParse("http://localhost/a"),
+			}},
+			wantErr: nil,
+		},
+		{
+			name:        "expect crawl canceled given a paused crawl is cancelled",
+			givenCancel: true,
+			want:        &Report{},
+			wantErr:     ErrCanceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scraped int32
+
+			scraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				atomic.AddInt32(&scraped, 1)
+
+				if link.URL.Path == "" {
+					return []*url.URL{testutil.URLMustParse("http://localhost/a")}, nil
+				}
+
+				return nil, nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+			)
+
+			c.Pause()
+
+			if !cmp.Equal(c.Paused(), true) {
+				t.Error(cmp.Diff(c.Paused(), true))
+			}
+
+			type result struct {
+				report *Report
+				err    error
+			}
+
+			done := make(chan result)
+
+			go func() {
+				report, err := c.CrawlContext(ctx, testutil.URLMustParse("http://localhost"))
+				done <- result{report: report, err: err}
+			}()
+
+			select {
+			case <-done:
+				t.Fatal("expected paused crawl not to finish")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			if !cmp.Equal(atomic.LoadInt32(&scraped), int32(0)) {
+				t.Error(cmp.Diff(atomic.LoadInt32(&scraped), int32(0)))
+			}
+
+			if tt.givenCancel {
+				cancel()
+			} else {
+				c.Resume()
+			}
+
+			got := <-done
+			if !cmp.Equal(got.err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(got.err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got.report, tt.want, ignoreStats) {
+				t.Error(cmp.Diff(got.report, tt.want, ignoreStats))
+			}
+
+			if !cmp.Equal(c.Paused(), tt.givenCancel) {
+				t.Error(cmp.Diff(c.Paused(), tt.givenCancel))
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package crawler
+
+// FailurePolicy decides whether a crawl should stop after a URL fails.
+type FailurePolicy interface {
+	// Abort reports whether the crawl should stop given the number of
+	// failed URLs and the number of URLs crawled so far, including failures.
+	Abort(failed, crawled int) bool
+}
+
+// FailurePolicyFunc is an adapter to use an ordinary function as a FailurePolicy.
+type FailurePolicyFunc func(failed, crawled int) bool
+
+// Abort calls f(failed, crawled).
+func (f FailurePolicyFunc) Abort(failed, crawled int) bool {
+	return f(failed, crawled)
+}
+
+// ContinueOnError is a FailurePolicy that never stops a crawl.
+func ContinueOnError() FailurePolicy {
+	return FailurePolicyFunc(func(_, _ int) bool {
+		return false
+	})
+}
+
+// AbortOnError is a FailurePolicy that stops a crawl on the first failed URL.
+func AbortOnError() FailurePolicy {
+	return AbortAfter(1)
+}
+
+// AbortAfter is a FailurePolicy that stops a crawl once n URLs have failed.
+func AbortAfter(n int) FailurePolicy {
+	return FailurePolicyFunc(func(failed, _ int) bool {
+		return failed >= n
+	})
+}
+
+// AbortAtRate is a FailurePolicy that stops a crawl once the ratio of failed
+// URLs to crawled URLs reaches the given rate, after at least min URLs are crawled.
+func AbortAtRate(rate float64, min int) FailurePolicy {
+	return FailurePolicyFunc(func(failed, crawled int) bool {
+		if crawled == 0 || crawled < min {
+			return false
+		}
+
+		return float64(failed)/float64(crawled) >= rate
+	})
+}
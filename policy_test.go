@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFailurePolicy_Abort(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenPolicy  FailurePolicy
+		givenFailed  int
+		givenCrawled int
+		want         bool
+	}{
+		{
+			name:         "expect continue on error to never abort",
+			givenPolicy:  ContinueOnError(),
+			givenFailed:  100,
+			givenCrawled: 100,
+			want:         false,
+		},
+		{
+			name:         "expect abort on error to abort on first failure",
+			givenPolicy:  AbortOnError(),
+			givenFailed:  1,
+			givenCrawled: 100,
+			want:         true,
+		},
+		{
+			name:         "expect abort on error not to abort without failures",
+			givenPolicy:  AbortOnError(),
+			givenFailed:  0,
+			givenCrawled: 100,
+			want:         false,
+		},
+		{
+			name:         "expect abort after not to abort below the limit",
+			givenPolicy:  AbortAfter(3),
+			givenFailed:  2,
+			givenCrawled: 10,
+			want:         false,
+		},
+		{
+			name:         "expect abort after to abort at the limit",
+			givenPolicy:  AbortAfter(3),
+			givenFailed:  3,
+			givenCrawled: 10,
+			want:         true,
+		},
+		{
+			name:         "expect abort at rate not to abort below the minimum crawled",
+			givenPolicy:  AbortAtRate(0.5, 10),
+			givenFailed:  5,
+			givenCrawled: 5,
+			want:         false,
+		},
+		{
+			name:         "expect abort at rate not to abort below the rate",
+			givenPolicy:  AbortAtRate(0.5, 10),
+			givenFailed:  4,
+			givenCrawled: 10,
+			want:         false,
+		},
+		{
+			name:         "expect abort at rate to abort at the rate",
+			givenPolicy:  AbortAtRate(0.5, 10),
+			givenFailed:  5,
+			givenCrawled: 10,
+			want:         true,
+		},
+		{
+			name:         "expect abort at rate not to abort given nothing crawled",
+			givenPolicy:  AbortAtRate(0.5, 0),
+			givenFailed:  0,
+			givenCrawled: 0,
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.givenPolicy.Abort(tt.givenFailed, tt.givenCrawled)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,523 @@
+package crawler
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+)
+
+// maxDeferred is the number of links held back for saturated hosts before
+// the queue waits for a link to finish instead of popping the frontier.
+const maxDeferred = 1000
+
+// maxQueued is the number of URLs waiting in the frontier that are remembered so
+// they are not pushed again, a URL pushed once the limit is reached is checked
+// with the Storer when it is popped instead.
+const maxQueued = 1 << 16
+
+// queue schedules the links of a Frontier for the workers of a single crawl.
+// It tracks the links being worked on so the workers know when the crawl
+// is finished: the frontier is empty and nothing is in flight. A URL waiting
+// in the frontier is not pushed again, so the frontier grows with the pages
+// found rather than the links to them.
+//
+// Links for a host that already has maxPerHost links in flight, or that is
+// throttled, has an open circuit breaker or is not ready for the Scheduler,
+// are deferred until the host is available, so the workers are spread across hosts.
+//
+// A stopped queue empties its frontier unless the frontier is a Persister,
+// the deferred links and the links found or retried after the stop are then
+// pushed to it so they are crawled by a later crawl.
+type queue struct {
+	mu         *sync.Mutex
+	cond       *sync.Cond
+	frontier   Frontier
+	persister  Persister
+	scheduler  Scheduler
+	maxPerHost int
+	throttle   *throttle
+	breaker    *breaker
+	inflight   map[*page.Link]bool
+	queued     map[url.URL]bool
+	hosts      map[string]int
+	waiting    map[string]*page.Link
+	probes     map[string]*page.Link
+	deferred   []*page.Link
+	notBefore  map[*page.Link]time.Time
+	timer      *time.Timer
+	wake       time.Time
+	reason     error
+	cause      error
+}
+
+// newQueue initializes a new queue that schedules links from the frontier,
+// a maxPerHost of zero does not limit the links in flight for a host.
+func newQueue(frontier Frontier, limiter Limiter, maxPerHost int, throttle *throttle, breaker *breaker) *queue {
+	mu := &sync.Mutex{}
+	persister, _ := frontier.(Persister)
+	scheduler, _ := limiter.(Scheduler)
+
+	return &queue{
+		mu:         mu,
+		cond:       sync.NewCond(mu),
+		frontier:   frontier,
+		persister:  persister,
+		scheduler:  scheduler,
+		maxPerHost: maxPerHost,
+		throttle:   throttle,
+		breaker:    breaker,
+		inflight:   map[*page.Link]bool{},
+		queued:     map[url.URL]bool{},
+		hosts:      map[string]int{},
+		waiting:    map[string]*page.Link{},
+		probes:     map[string]*page.Link{},
+		notBefore:  map[*page.Link]time.Time{},
+	}
+}
+
+// pop blocks until a link is available and marks it as in flight.
+// It returns false once the frontier is empty and no link is in flight,
+// or the queue has been stopped. The queue is stopped with ErrFrontier
+// if the frontier fails.
+func (q *queue) pop() (*page.Link, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.reason == nil {
+		now := time.Now()
+
+		if link := q.undefer(now); link != nil {
+			q.start(link, now)
+
+			return link, true
+		}
+
+		if q.frontier.Len() > 0 && len(q.deferred) < maxDeferred {
+			link, err := q.frontier.Pop()
+			if err != nil {
+				q.stopLocked(ErrFrontier, err)
+
+				return nil, false
+			}
+
+			delete(q.queued, *link.URL)
+
+			if q.blocked(link, now) {
+				q.deferred = append(q.deferred, link)
+
+				continue
+			}
+
+			q.start(link, now)
+
+			return link, true
+		}
+
+		if len(q.inflight) == 0 && q.frontier.Len() == 0 && len(q.deferred) == 0 {
+			return nil, false
+		}
+
+		q.schedule(now)
+		q.cond.Wait()
+	}
+
+	return nil, false
+}
+
+// undefer removes and returns the first deferred link for a host that is not blocked.
+func (q *queue) undefer(now time.Time) *page.Link {
+	for i, link := range q.deferred {
+		if q.blocked(link, now) {
+			continue
+		}
+
+		copy(q.deferred[i:], q.deferred[i+1:])
+		q.deferred[len(q.deferred)-1] = nil
+		q.deferred = q.deferred[:len(q.deferred)-1]
+
+		return link
+	}
+
+	return nil
+}
+
+// blocked reports whether the host of the link has maxPerHost links in flight, is throttled,
+// its circuit breaker defers it, has a link waiting for the Scheduler or is not ready for it,
+// or the link is a retry that is waiting.
+func (q *queue) blocked(link *page.Link, now time.Time) bool {
+	if q.notBefore[link].After(now) {
+		return true
+	}
+
+	host := link.URL.Hostname()
+
+	if _, ok := q.breaker.ready(host, now); !ok && q.breaker.mode == BreakerDefer {
+		return true
+	}
+
+	if q.maxPerHost > 0 && q.hosts[host] >= q.maxPerHost {
+		return true
+	}
+
+	if q.scheduler != nil && (q.waiting[host] != nil || q.scheduler.Ready(link.URL).After(now)) {
+		return true
+	}
+
+	return q.throttle.ready(host).After(now)
+}
+
+// schedule wakes the waiting workers when the first throttled, open or
+// not ready host or waiting retry of the deferred links is ready.
+func (q *queue) schedule(now time.Time) {
+	var wake time.Time
+
+	for _, link := range q.deferred {
+		ready := q.throttle.ready(link.URL.Hostname())
+		if at := q.notBefore[link]; at.After(ready) {
+			ready = at
+		}
+
+		if q.scheduler != nil {
+			if at := q.scheduler.Ready(link.URL); at.After(ready) {
+				ready = at
+			}
+		}
+
+		if at, _ := q.breaker.ready(link.URL.Hostname(), now); q.breaker.mode == BreakerDefer && at.After(ready) {
+			ready = at
+		}
+
+		if ready.After(now) && (wake.IsZero() || ready.Before(wake)) {
+			wake = ready
+		}
+	}
+
+	if wake.IsZero() || (q.timer != nil && !wake.Before(q.wake)) {
+		return
+	}
+
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+
+	var timer *time.Timer
+
+	timer = time.AfterFunc(wake.Sub(now), func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if q.timer == timer {
+			q.timer = nil
+		}
+
+		q.cond.Broadcast()
+	})
+
+	q.timer = timer
+	q.wake = wake
+}
+
+func (q *queue) start(link *page.Link, now time.Time) {
+	host := link.URL.Hostname()
+
+	delete(q.notBefore, link)
+
+	q.inflight[link] = false
+	q.hosts[host]++
+	q.throttle.dispatched(host, now)
+
+	if q.scheduler != nil {
+		q.waiting[host] = link
+	}
+
+	if q.breaker.mode == BreakerDefer {
+		q.take(link, now)
+	}
+}
+
+// take acquires the circuit breaker of the host of the link, recording the link if it is the probe.
+func (q *queue) take(link *page.Link, now time.Time) bool {
+	host := link.URL.Hostname()
+
+	ok, probe := q.breaker.acquire(host, now)
+	if probe {
+		q.probes[host] = link
+	}
+
+	return ok
+}
+
+// acquire reports whether a request can be sent for an in flight link,
+// it is false if the circuit breaker of its host fails fast.
+func (q *queue) acquire(link *page.Link) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.breaker.mode != BreakerFailFast {
+		return true
+	}
+
+	return q.take(link, time.Now())
+}
+
+// waited marks an in flight link as no longer waiting for the Limiter,
+// so the next link for its host can be started.
+func (q *queue) waited(link *page.Link) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.unwait(link)
+}
+
+func (q *queue) unwait(link *page.Link) {
+	host := link.URL.Hostname()
+
+	if q.waiting[host] == link {
+		delete(q.waiting, host)
+		q.cond.Broadcast()
+	}
+}
+
+// observe passes the response or error of an in flight link to the throttle and circuit breaker.
+func (q *queue) observe(link *page.Link, resp *page.Response, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	host := link.URL.Hostname()
+
+	if resp != nil {
+		q.throttle.observe(host, resp, now)
+	}
+
+	if q.probes[host] == link {
+		delete(q.probes, host)
+	}
+
+	q.breaker.observe(host, resp, err, now)
+}
+
+// push adds the links to the frontier, returning the first link that failed and its error.
+func (q *queue) push(links ...*page.Link) (*page.Link, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.pushLocked(links)
+}
+
+// claim marks an in flight link as written to the Storer.
+func (q *queue) claim(link *page.Link) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.inflight[link] = true
+}
+
+// done marks an in flight link as finished and pushes the links found on it,
+// returning the first link that failed and its error.
+func (q *queue) done(link *page.Link, found ...*page.Link) (*page.Link, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.finish(link)
+
+	return q.pushLocked(found)
+}
+
+// retry marks an in flight link as finished and defers the retry of it until the given time.
+func (q *queue) retry(link, retry *page.Link, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.finish(link)
+
+	if q.reason != nil {
+		q.keep(retry)
+
+		return
+	}
+
+	q.deferred = append(q.deferred, retry)
+	q.notBefore[retry] = at
+}
+
+// requeue marks an in flight link of a stopped queue that was not crawled as finished,
+// it is kept for a later crawl by a Persister frontier.
+func (q *queue) requeue(link *page.Link) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.finish(link)
+	q.keep(link)
+}
+
+// keep pushes the links of a stopped queue back to a Persister frontier.
+// A link that fails to push is lost as it would be with any other frontier.
+func (q *queue) keep(links ...*page.Link) {
+	if q.persister == nil {
+		return
+	}
+
+	for _, link := range links {
+		_ = q.frontier.Push(link)
+	}
+}
+
+// persist calls Persist on a Persister frontier.
+func (q *queue) persist() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.persister == nil {
+		return nil
+	}
+
+	return q.persister.Persist()
+}
+
+func (q *queue) finish(link *page.Link) {
+	delete(q.inflight, link)
+	q.unwait(link)
+
+	host := link.URL.Hostname()
+
+	// A probe that finishes without a response, such as a link the enforcer
+	// rejects or a cancelled request, is released for the next link of its host.
+	if q.probes[host] == link {
+		delete(q.probes, host)
+		q.breaker.release(host)
+	}
+
+	if q.hosts[host]--; q.hosts[host] <= 0 {
+		delete(q.hosts, host)
+	}
+
+	q.cond.Broadcast()
+}
+
+func (q *queue) pushLocked(links []*page.Link) (*page.Link, error) {
+	if q.reason != nil && q.persister == nil {
+		return nil, nil
+	}
+
+	var (
+		failed *page.Link
+		err    error
+	)
+
+	for _, link := range links {
+		if q.queued[*link.URL] {
+			continue
+		}
+
+		pushErr := q.frontier.Push(link)
+		if pushErr == nil {
+			if len(q.queued) < maxQueued {
+				q.queued[*link.URL] = true
+			}
+
+			continue
+		}
+
+		if err == nil {
+			failed, err = link, pushErr
+		}
+	}
+
+	q.cond.Broadcast()
+
+	return failed, err
+}
+
+// stop discards the links in the frontier so no more links are popped,
+// links already in flight are left to finish. A Persister frontier is not
+// emptied and the deferred links are pushed back to it. The reason and cause
+// of the first call to stop are kept.
+func (q *queue) stop(reason, cause error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.stopLocked(reason, cause)
+}
+
+func (q *queue) stopLocked(reason, cause error) {
+	if q.reason != nil {
+		return
+	}
+
+	q.reason = reason
+	q.cause = cause
+
+	q.keep(q.deferred...)
+
+	for q.persister == nil && q.frontier.Len() > 0 {
+		if _, err := q.frontier.Pop(); err != nil {
+			break
+		}
+	}
+
+	q.deferred = nil
+	q.notBefore = map[*page.Link]time.Time{}
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	q.cond.Broadcast()
+}
+
+// snapshot returns the links waiting in the frontier followed by the deferred links and
+// the links in flight, and the links in flight that have been claimed. It reports false
+// if the queue is stopped. A Persister frontier is persisted instead of listed.
+func (q *queue) snapshot() (pending, claimed []*page.Link, ok bool, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.reason != nil {
+		return nil, nil, false, nil
+	}
+
+	switch frontier := q.frontier.(type) {
+	case Persister:
+		err = frontier.Persist()
+	case Snapshotter:
+		pending, err = frontier.Snapshot()
+	default:
+		err = errNoSnapshot
+	}
+
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	pending = append(pending, q.deferred...)
+
+	for link, isClaimed := range q.inflight {
+		pending = append(pending, link)
+
+		if isClaimed {
+			claimed = append(claimed, link)
+		}
+	}
+
+	return pending, claimed, true, nil
+}
+
+// len returns the number of links waiting in the frontier or deferred.
+func (q *queue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.frontier.Len() + len(q.deferred)
+}
+
+// stopped returns the reason and cause the queue was stopped, nil if it was not.
+func (q *queue) stopped() (reason, cause error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.reason, q.cause
+}
@@ -0,0 +1,131 @@
+package get
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/clarke94/crawler/page"
+)
+
+// Kind is the kind of failure of a request.
+type Kind int
+
+const (
+	// KindUnknown is a failure that is not classified.
+	KindUnknown Kind = iota
+	// KindRequest is a request that cannot be created, such as an invalid URL.
+	KindRequest
+	// KindCanceled is a request cancelled by its context.
+	KindCanceled
+	// KindTimeout is a request that timed out.
+	KindTimeout
+	// KindDNS is a host name that cannot be resolved.
+	KindDNS
+	// KindTLS is a failed TLS handshake or an invalid certificate.
+	KindTLS
+	// KindConnection is a connection that is refused, reset or closed.
+	KindConnection
+	// KindStatus is a response with a status code other than 2xx.
+	KindStatus
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindRequest:
+		return "request"
+	case KindCanceled:
+		return "canceled"
+	case KindTimeout:
+		return "timeout"
+	case KindDNS:
+		return "dns"
+	case KindTLS:
+		return "tls"
+	case KindConnection:
+		return "connection"
+	case KindStatus:
+		return "status"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is the error returned by Get, it wraps the cause of the failure so it can be
+// inspected with errors.As and is ErrRequest or ErrDo depending on where it failed.
+type Error struct {
+	// Kind is the kind of failure.
+	Kind Kind
+	// StatusCode is the status code of the response for KindStatus.
+	StatusCode int
+	// Err is the cause of the failure.
+	Err error
+
+	sentinel error
+}
+
+// Error returns the sentinel and the cause of the failure.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v: %v", e.sentinel, e.Kind)
+	}
+
+	return fmt.Sprintf("%v: %v: %v", e.sentinel, e.Kind, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrRequest or ErrDo for where the request failed.
+func (e *Error) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// CheckStatus returns an Error of KindStatus if the response is not 2xx.
+func CheckStatus(resp *page.Response) error {
+	if resp.OK() {
+		return nil
+	}
+
+	return &Error{Kind: KindStatus, StatusCode: resp.StatusCode, sentinel: ErrDo}
+}
+
+// classify returns the kind of failure of the error from the client.
+func classify(err error) Kind {
+	var (
+		dnsErr     *net.DNSError
+		netErr     net.Error
+		opErr      *net.OpError
+		recordErr  tls.RecordHeaderError
+		authErr    x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	case errors.As(err, &dnsErr):
+		return KindDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return KindTimeout
+	case errors.As(err, &recordErr), errors.As(err, &authErr), errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return KindTLS
+	case errors.As(err, &opErr),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return KindConnection
+	default:
+		return KindUnknown
+	}
+}
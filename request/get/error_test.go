@@ -0,0 +1,165 @@
+package get
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var errTest = errors.New("test")
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		givenErr error
+		want     Kind
+	}{
+		{
+			name:     "expect canceled given a cancelled context",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: context.Canceled},
+			want:     KindCanceled,
+		},
+		{
+			name:     "expect timeout given an exceeded deadline",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: context.DeadlineExceeded},
+			want:     KindTimeout,
+		},
+		{
+			name: "expect dns given a host that does not exist",
+			givenErr: &url.Error{
+				Op:  "Get",
+				URL: "http://localhost",
+				Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}},
+			},
+			want: KindDNS,
+		},
+		{
+			name:     "expect tls given an unknown certificate authority",
+			givenErr: &url.Error{Op: "Get", URL: "https://localhost", Err: x509.UnknownAuthorityError{}},
+			want:     KindTLS,
+		},
+		{
+			name:     "expect tls given a certificate for another host",
+			givenErr: &url.Error{Op: "Get", URL: "https://localhost", Err: x509.HostnameError{Host: "localhost"}},
+			want:     KindTLS,
+		},
+		{
+			name:     "expect connection given a refused connection",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}},
+			want:     KindConnection,
+		},
+		{
+			name:     "expect connection given a closed connection",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: io.EOF},
+			want:     KindConnection,
+		},
+		{
+			name:     "expect unknown given any other error",
+			givenErr: errTest,
+			want:     KindUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(tt.givenErr)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenErr    error
+		givenTarget error
+		want        bool
+	}{
+		{
+			name:        "expect do error given ErrDo",
+			givenErr:    &Error{Kind: KindTimeout, Err: errTest, sentinel: ErrDo},
+			givenTarget: ErrDo,
+			want:        true,
+		},
+		{
+			name:        "expect request error given ErrRequest",
+			givenErr:    &Error{Kind: KindRequest, Err: errTest, sentinel: ErrRequest},
+			givenTarget: ErrRequest,
+			want:        true,
+		},
+		{
+			name:        "expect not request error given ErrDo",
+			givenErr:    &Error{Kind: KindTimeout, Err: errTest, sentinel: ErrDo},
+			givenTarget: ErrRequest,
+			want:        false,
+		},
+		{
+			name:        "expect cause given the wrapped error",
+			givenErr:    &Error{Kind: KindTimeout, Err: errTest, sentinel: ErrDo},
+			givenTarget: errTest,
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.Is(tt.givenErr, tt.givenTarget)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenResp  *page.Response
+		wantErr    error
+		wantStatus int
+	}{
+		{
+			name:       "expect nil given a successful response",
+			givenResp:  &page.Response{StatusCode: http.StatusOK},
+			wantErr:    nil,
+			wantStatus: 0,
+		},
+		{
+			name:       "expect status error given a not found response",
+			givenResp:  &page.Response{StatusCode: http.StatusNotFound},
+			wantErr:    ErrDo,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckStatus(tt.givenResp)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			var (
+				getErr *Error
+				got    int
+			)
+
+			if errors.As(err, &getErr) {
+				got = getErr.StatusCode
+			}
+
+			if !cmp.Equal(got, tt.wantStatus) {
+				t.Error(cmp.Diff(got, tt.wantStatus))
+			}
+		})
+	}
+}
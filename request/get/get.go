@@ -5,7 +5,11 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/clarke94/crawler/page"
 )
 
 var (
@@ -17,40 +21,233 @@ var (
 	ErrDo = errors.New("unable to send request")
 )
 
-const timeout = 10
+const (
+	// defaultTimeout is the client timeout used when WithTimeout is not provided.
+	defaultTimeout = 10 * time.Second
+	// defaultMaxRedirects is the redirects followed when WithMaxRedirects is not provided.
+	defaultMaxRedirects = 10
+)
 
 // Get is a Requester for HTTP GET requests.
 type Get struct {
-	client *http.Client
+	client    *http.Client
+	transport *http.Transport
+	header    http.Header
+
+	maxRedirects int
+	sameHost     bool
+	sameScope    bool
+}
+
+// Option is a functional option to modify the default Get instance.
+type Option func(r *Get)
+
+// WithConnections sets the idle connections kept open for reuse, in total and for each host,
+// and the maximum connections to a single host. Values less than zero are ignored, zero is no limit.
+// It only applies to the default transport.
+func WithConnections(maxIdle, maxIdlePerHost, maxPerHost int) Option {
+	return func(r *Get) {
+		if maxIdle >= 0 {
+			r.transport.MaxIdleConns = maxIdle
+		}
+
+		if maxIdlePerHost >= 0 {
+			r.transport.MaxIdleConnsPerHost = maxIdlePerHost
+		}
+
+		if maxPerHost >= 0 {
+			r.transport.MaxConnsPerHost = maxPerHost
+		}
+	}
+}
+
+// WithCookieJar sets the jar that keeps the cookies of each host between requests,
+// by default cookies are not kept.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(r *Get) {
+		r.client.Jar = jar
+	}
+}
+
+// WithHeader adds a header sent with every request, it can be provided more than once.
+func WithHeader(key, value string) Option {
+	return func(r *Get) {
+		r.header.Add(key, value)
+	}
+}
+
+// WithIdleTimeout sets how long an idle connection is kept open for reuse,
+// zero is no limit. It only applies to the default transport.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(r *Get) {
+		r.transport.IdleConnTimeout = d
+	}
+}
+
+// WithMaxRedirects sets the maximum number of redirects followed for a request, the
+// redirect response is returned once it is reached. Values less than zero are ignored.
+func WithMaxRedirects(n int) Option {
+	return func(r *Get) {
+		if n < 0 {
+			return
+		}
+
+		r.maxRedirects = n
+	}
+}
+
+// WithSameHostRedirects only follows redirects to the host of the request,
+// a redirect to another host is returned as the response.
+func WithSameHostRedirects() Option {
+	return func(r *Get) {
+		r.sameHost = true
+	}
+}
+
+// WithSameScopeRedirects only follows redirects to the hosts in the scope of the request,
+// such as the hosts of the seeds of a crawl, a redirect outside of the scope is returned as
+// the response without requesting it. The scope is read from the context of the request with
+// page.ScopeFromContext, a request without a scope is only redirected to its own host.
+func WithSameScopeRedirects() Option {
+	return func(r *Get) {
+		r.sameScope = true
+	}
+}
+
+// WithTimeout replaces the default 10 second timeout of a request, including
+// reading the body. A timeout of zero is no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(r *Get) {
+		r.client.Timeout = d
+	}
+}
+
+// WithTransport replaces the default transport used to send requests with the provided one.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Get) {
+		r.client.Transport = transport
+	}
+}
+
+// WithUserAgent replaces the default Go User-Agent sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(r *Get) {
+		r.header.Set("User-Agent", userAgent)
+	}
 }
 
 // New initializes a new Get Requester.
-func New() *Get {
-	client := &http.Client{
-		Timeout: timeout * time.Second,
+func New(options ...Option) *Get {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	r := &Get{
+		client: &http.Client{
+			Timeout:   defaultTimeout,
+			Transport: transport,
+		},
+		transport: transport,
+		header:    http.Header{},
+
+		maxRedirects: defaultMaxRedirects,
 	}
 
-	return &Get{
-		client: client,
+	r.client.CheckRedirect = r.checkRedirect
+
+	for _, opt := range options {
+		opt(r)
 	}
+
+	return r
 }
 
-// Request parses the given URL and returns a HTTP request.
+// Request parses the given URL and returns a HTTP request with the default headers.
 func (r *Get) Request(ctx context.Context, rawURL string, _ io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return nil, ErrRequest
+		return nil, &Error{Kind: KindRequest, Err: err, sentinel: ErrRequest}
 	}
 
+	r.setHeader(req)
+
 	return req, nil
 }
 
+// PostForm sends a HTTP POST request with the form data and the default headers, such as to
+// log in before a crawl. The cookies set by the response are kept with WithCookieJar.
+func (r *Get) PostForm(ctx context.Context, rawURL string, data url.Values) (*page.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, &Error{Kind: KindRequest, Err: err, sentinel: ErrRequest}
+	}
+
+	r.setHeader(req)
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return r.Do(req)
+}
+
 // Do sends a HTTP request and returns the response.
-func (r *Get) Do(req *http.Request) (io.ReadCloser, error) {
+func (r *Get) Do(req *http.Request) (*page.Response, error) {
+	start := time.Now()
+
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, ErrDo
+		return nil, &Error{Kind: classify(err), Err: err, sentinel: ErrDo}
+	}
+
+	return &page.Response{
+		Request:    req,
+		URL:        resp.Request.URL,
+		Redirects:  redirects(resp),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Duration:   time.Since(start),
+		Body:       resp.Body,
+	}, nil
+}
+
+// setHeader adds the default headers to the request.
+func (r *Get) setHeader(req *http.Request) {
+	for key, values := range r.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
+
+// checkRedirect stops following redirects once the max redirects are followed, the redirect
+// is to another host with WithSameHostRedirects or out of scope with WithSameScopeRedirects.
+func (r *Get) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > r.maxRedirects {
+		return http.ErrUseLastResponse
+	}
+
+	if r.sameHost && req.URL.Hostname() != via[0].URL.Hostname() {
+		return http.ErrUseLastResponse
+	}
+
+	if r.sameScope && !scope(via[0]).Contains(req.URL) {
+		return http.ErrUseLastResponse
+	}
+
+	return nil
+}
+
+// scope returns the scope of the request, or the host of the request if it has none.
+func scope(req *http.Request) page.Scope {
+	if s := page.ScopeFromContext(req.Context()); s != nil {
+		return s
+	}
+
+	return page.NewScope(req.URL)
+}
+
+// redirects returns the URLs the response was redirected from in order.
+func redirects(resp *http.Response) []*url.URL {
+	var urls []*url.URL
+
+	for req := resp.Request; req.Response != nil; req = req.Response.Request {
+		urls = append([]*url.URL{req.Response.Request.URL}, urls...)
 	}
 
-	return resp.Body, nil
+	return urls
 }
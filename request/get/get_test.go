@@ -2,12 +2,18 @@ package get
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -32,12 +38,98 @@ func TestNewGet(t *testing.T) {
 	}
 }
 
+func TestNewGet_Options(t *testing.T) {
+	tests := []struct {
+		name                 string
+		givenOptions         []Option
+		wantTimeout          time.Duration
+		wantHeader           http.Header
+		wantConnections      [3]int
+		wantIdleTimeout      time.Duration
+		wantDefaultTransport bool
+	}{
+		{
+			name:                 "expect defaults given no options",
+			givenOptions:         nil,
+			wantTimeout:          10 * time.Second,
+			wantHeader:           http.Header{},
+			wantConnections:      [3]int{100, 0, 0},
+			wantIdleTimeout:      90 * time.Second,
+			wantDefaultTransport: true,
+		},
+		{
+			name: "expect options applied",
+			givenOptions: []Option{
+				WithTimeout(time.Minute),
+				WithUserAgent("crawler/1.0"),
+				WithHeader("Accept-Language", "en"),
+				WithHeader("Accept-Language", "fr"),
+				WithConnections(50, 5, 10),
+				WithIdleTimeout(time.Second),
+			},
+			wantTimeout: time.Minute,
+			wantHeader: http.Header{
+				"User-Agent":      []string{"crawler/1.0"},
+				"Accept-Language": []string{"en", "fr"},
+			},
+			wantConnections:      [3]int{50, 5, 10},
+			wantIdleTimeout:      time.Second,
+			wantDefaultTransport: true,
+		},
+		{
+			name:                 "expect connections left as is given negative values",
+			givenOptions:         []Option{WithConnections(-1, -1, -1)},
+			wantTimeout:          10 * time.Second,
+			wantHeader:           http.Header{},
+			wantConnections:      [3]int{100, 0, 0},
+			wantIdleTimeout:      90 * time.Second,
+			wantDefaultTransport: true,
+		},
+		{
+			name:                 "expect transport replaced",
+			givenOptions:         []Option{WithTransport(mockRoundTripper{})},
+			wantTimeout:          10 * time.Second,
+			wantHeader:           http.Header{},
+			wantConnections:      [3]int{100, 0, 0},
+			wantIdleTimeout:      90 * time.Second,
+			wantDefaultTransport: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.givenOptions...)
+
+			if !cmp.Equal(got.client.Timeout, tt.wantTimeout) {
+				t.Error(cmp.Diff(got.client.Timeout, tt.wantTimeout))
+			}
+
+			if !cmp.Equal(got.header, tt.wantHeader) {
+				t.Error(cmp.Diff(got.header, tt.wantHeader))
+			}
+
+			connections := [3]int{got.transport.MaxIdleConns, got.transport.MaxIdleConnsPerHost, got.transport.MaxConnsPerHost}
+			if !cmp.Equal(connections, tt.wantConnections) {
+				t.Error(cmp.Diff(connections, tt.wantConnections))
+			}
+
+			if !cmp.Equal(got.transport.IdleConnTimeout, tt.wantIdleTimeout) {
+				t.Error(cmp.Diff(got.transport.IdleConnTimeout, tt.wantIdleTimeout))
+			}
+
+			if !cmp.Equal(got.client.Transport == got.transport, tt.wantDefaultTransport) {
+				t.Error(cmp.Diff(got.client.Transport == got.transport, tt.wantDefaultTransport))
+			}
+		})
+	}
+}
+
 func TestGet_Request(t *testing.T) {
 	tests := []struct {
-		name     string
-		givenURL string
-		want     *http.Request
-		wantErr  error
+		name         string
+		givenOptions []Option
+		givenURL     string
+		want         *http.Request
+		wantErr      error
 	}{
 		{
 			name:     "expect a GET request for the given URL",
@@ -53,6 +145,24 @@ func TestGet_Request(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:         "expect default headers given header options",
+			givenOptions: []Option{WithUserAgent("crawler/1.0"), WithHeader("Accept", "text/html")},
+			givenURL:     "http://localhost",
+			want: &http.Request{
+				Method:     "GET",
+				URL:        testutil.URLMustParse("http://localhost"),
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header: http.Header{
+					"User-Agent": []string{"crawler/1.0"},
+					"Accept":     []string{"text/html"},
+				},
+				Host: "localhost",
+			},
+			wantErr: nil,
+		},
 		{
 			name:     "expect error given an invalid URL",
 			givenURL: "%",
@@ -62,7 +172,7 @@ func TestGet_Request(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := New()
+			r := New(tt.givenOptions...)
 
 			got, err := r.Request(context.Background(), tt.givenURL, nil)
 			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
@@ -82,6 +192,7 @@ func TestGet_Do(t *testing.T) {
 		givenRequest *http.Request
 		givenHandler testutil.Handler
 		want         []byte
+		wantStatus   int
 		wantErr      error
 	}{
 		{
@@ -96,8 +207,9 @@ func TestGet_Do(t *testing.T) {
 					}
 				},
 			},
-			want:    []byte("foo"),
-			wantErr: nil,
+			want:       []byte("foo"),
+			wantStatus: http.StatusOK,
+			wantErr:    nil,
 		},
 		{
 			name:         "expect empty body given status response",
@@ -108,8 +220,9 @@ func TestGet_Do(t *testing.T) {
 					rw.WriteHeader(http.StatusInternalServerError)
 				},
 			},
-			want:    []byte{},
-			wantErr: nil,
+			want:       []byte{},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    nil,
 		},
 	}
 	for _, tt := range tests {
@@ -124,9 +237,17 @@ func TestGet_Do(t *testing.T) {
 				t.Fatal(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
 			}
 
-			defer got.Close()
+			defer got.Body.Close()
+
+			if !cmp.Equal(got.StatusCode, tt.wantStatus) {
+				t.Fatal(cmp.Diff(got.StatusCode, tt.wantStatus))
+			}
+
+			if !cmp.Equal(got.URL, tt.givenRequest.URL) {
+				t.Fatal(cmp.Diff(got.URL, tt.givenRequest.URL))
+			}
 
-			body, err := ioutil.ReadAll(got)
+			body, err := ioutil.ReadAll(got.Body)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -138,6 +259,272 @@ func TestGet_Do(t *testing.T) {
 	}
 }
 
+func TestGet_Do_Redirect(t *testing.T) {
+	redirect := func(target string) http.HandlerFunc {
+		return func(rw http.ResponseWriter, rr *http.Request) {
+			http.Redirect(rw, rr, target, http.StatusFound)
+		}
+	}
+
+	handlers := []testutil.Handler{
+		{Pattern: "/a", HandlerFunc: redirect("/b")},
+		{Pattern: "/b", HandlerFunc: redirect("/c")},
+		{Pattern: "/c", HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {}},
+		{Pattern: "/off", HandlerFunc: redirect("http://localhost:8080/c")},
+	}
+
+	tests := []struct {
+		name          string
+		givenOptions  []Option
+		givenScope    page.Scope
+		givenURL      string
+		wantStatus    int
+		wantURL       *url.URL
+		wantRedirects []*url.URL
+	}{
+		{
+			name:          "expect no redirects given a response",
+			givenURL:      "http://127.0.0.1:8080/c",
+			wantStatus:    http.StatusOK,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/c"),
+			wantRedirects: nil,
+		},
+		{
+			name:       "expect redirect chain given redirects followed",
+			givenURL:   "http://127.0.0.1:8080/a",
+			wantStatus: http.StatusOK,
+			wantURL:    testutil.URLMustParse("http://127.0.0.1:8080/c"),
+			wantRedirects: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/a"),
+				testutil.URLMustParse("http://127.0.0.1:8080/b"),
+			},
+		},
+		{
+			name:          "expect redirect response given max redirects reached",
+			givenOptions:  []Option{WithMaxRedirects(1)},
+			givenURL:      "http://127.0.0.1:8080/a",
+			wantStatus:    http.StatusFound,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/b"),
+			wantRedirects: []*url.URL{testutil.URLMustParse("http://127.0.0.1:8080/a")},
+		},
+		{
+			name:          "expect redirect response given no redirects followed",
+			givenOptions:  []Option{WithMaxRedirects(0)},
+			givenURL:      "http://127.0.0.1:8080/a",
+			wantStatus:    http.StatusFound,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/a"),
+			wantRedirects: nil,
+		},
+		{
+			name:          "expect redirect response given a redirect to another host",
+			givenOptions:  []Option{WithSameHostRedirects()},
+			givenURL:      "http://127.0.0.1:8080/off",
+			wantStatus:    http.StatusFound,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/off"),
+			wantRedirects: nil,
+		},
+		{
+			name:         "expect redirect followed given a redirect to a host in the scope",
+			givenOptions: []Option{WithSameScopeRedirects()},
+			givenScope:   page.Scope{"127.0.0.1": true, "localhost": true},
+			givenURL:     "http://127.0.0.1:8080/off",
+			wantStatus:   http.StatusOK,
+			wantURL:      testutil.URLMustParse("http://localhost:8080/c"),
+			wantRedirects: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/off"),
+			},
+		},
+		{
+			name:          "expect redirect response given a redirect out of the scope",
+			givenOptions:  []Option{WithSameScopeRedirects()},
+			givenScope:    page.Scope{"127.0.0.1": true},
+			givenURL:      "http://127.0.0.1:8080/off",
+			wantStatus:    http.StatusFound,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/off"),
+			wantRedirects: nil,
+		},
+		{
+			name:          "expect redirect response given a redirect to another host and no scope",
+			givenOptions:  []Option{WithSameScopeRedirects()},
+			givenURL:      "http://127.0.0.1:8080/off",
+			wantStatus:    http.StatusFound,
+			wantURL:       testutil.URLMustParse("http://127.0.0.1:8080/off"),
+			wantRedirects: nil,
+		},
+		{
+			name:       "expect redirect to another host followed by default",
+			givenURL:   "http://127.0.0.1:8080/off",
+			wantStatus: http.StatusOK,
+			wantURL:    testutil.URLMustParse("http://localhost:8080/c"),
+			wantRedirects: []*url.URL{
+				testutil.URLMustParse("http://127.0.0.1:8080/off"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := testutil.NewTestServer(handlers...)
+			defer ts.Close()
+
+			r := New(tt.givenOptions...)
+
+			ctx := page.ContextWithScope(context.Background(), tt.givenScope)
+
+			got, err := r.Do(testutil.HTTPMustRequests(ctx, http.MethodGet, tt.givenURL, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer got.Body.Close()
+
+			if !cmp.Equal(got.StatusCode, tt.wantStatus) {
+				t.Error(cmp.Diff(got.StatusCode, tt.wantStatus))
+			}
+
+			if !cmp.Equal(got.URL, tt.wantURL) {
+				t.Error(cmp.Diff(got.URL, tt.wantURL))
+			}
+
+			if !cmp.Equal(got.Redirects, tt.wantRedirects) {
+				t.Error(cmp.Diff(got.Redirects, tt.wantRedirects))
+			}
+		})
+	}
+}
+
+func TestGet_PostForm(t *testing.T) {
+	handlers := []testutil.Handler{
+		{
+			Pattern: "/login",
+			HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+				if rr.Method != http.MethodPost || rr.PostFormValue("user") != "crawler" {
+					rw.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				http.SetCookie(rw, &http.Cookie{Name: "session", Value: "1"})
+			},
+		},
+		{
+			Pattern: "/private",
+			HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+				if _, err := rr.Cookie("session"); err != nil {
+					rw.WriteHeader(http.StatusUnauthorized)
+				}
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		givenJar        bool
+		givenData       url.Values
+		wantLoginStatus int
+		wantStatus      int
+	}{
+		{
+			name:            "expect session kept given a cookie jar",
+			givenJar:        true,
+			givenData:       url.Values{"user": []string{"crawler"}},
+			wantLoginStatus: http.StatusOK,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "expect session lost given no cookie jar",
+			givenJar:        false,
+			givenData:       url.Values{"user": []string{"crawler"}},
+			wantLoginStatus: http.StatusOK,
+			wantStatus:      http.StatusUnauthorized,
+		},
+		{
+			name:            "expect no session given a failed login",
+			givenJar:        true,
+			givenData:       url.Values{"user": []string{"someone"}},
+			wantLoginStatus: http.StatusUnauthorized,
+			wantStatus:      http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := testutil.NewTestServer(handlers...)
+			defer ts.Close()
+
+			var options []Option
+
+			if tt.givenJar {
+				jar, err := cookiejar.New(nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				options = append(options, WithCookieJar(jar))
+			}
+
+			r := New(options...)
+
+			login, err := r.PostForm(context.Background(), "http://127.0.0.1:8080/login", tt.givenData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = login.Body.Close()
+
+			if !cmp.Equal(login.StatusCode, tt.wantLoginStatus) {
+				t.Error(cmp.Diff(login.StatusCode, tt.wantLoginStatus))
+			}
+
+			req, err := r.Request(context.Background(), "http://127.0.0.1:8080/private", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = got.Body.Close()
+
+			if !cmp.Equal(got.StatusCode, tt.wantStatus) {
+				t.Error(cmp.Diff(got.StatusCode, tt.wantStatus))
+			}
+		})
+	}
+}
+
+func TestGet_Do_Transport(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenRequest *http.Request
+		givenStatus  int
+		wantStatus   int
+	}{
+		{
+			name:         "expect response from the given transport",
+			givenRequest: testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil),
+			givenStatus:  http.StatusTeapot,
+			wantStatus:   http.StatusTeapot,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithTransport(mockRoundTripper{GivenStatus: tt.givenStatus}))
+
+			got, err := r.Do(tt.givenRequest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer got.Body.Close()
+
+			if !cmp.Equal(got.StatusCode, tt.wantStatus) {
+				t.Error(cmp.Diff(got.StatusCode, tt.wantStatus))
+			}
+		})
+	}
+}
+
 func TestGet_Do_Fail(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -167,3 +554,57 @@ func TestGet_Do_Fail(t *testing.T) {
 		})
 	}
 }
+
+func TestGet_Do_Error(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenRequest *http.Request
+		wantErr      error
+		wantCause    error
+		wantKind     Kind
+	}{
+		{
+			name:         "expect do error wrapping the cause given a refused connection",
+			givenRequest: testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://127.0.0.1:1", nil),
+			wantErr:      ErrDo,
+			wantCause:    syscall.ECONNREFUSED,
+			wantKind:     KindConnection,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+
+			_, err := r.Do(tt.givenRequest)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !errors.Is(err, tt.wantCause) {
+				t.Errorf("expected %v to wrap %v", err, tt.wantCause)
+			}
+
+			var getErr *Error
+			if !errors.As(err, &getErr) {
+				t.Fatalf("expected %v to be an Error", err)
+			}
+
+			if !cmp.Equal(getErr.Kind, tt.wantKind) {
+				t.Error(cmp.Diff(getErr.Kind, tt.wantKind))
+			}
+		})
+	}
+}
+
+type mockRoundTripper struct {
+	GivenStatus int
+}
+
+func (m mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Request:    req,
+		StatusCode: m.GivenStatus,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
@@ -0,0 +1,182 @@
+package method
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/clarke94/crawler/page"
+	"github.com/clarke94/crawler/request/get"
+)
+
+var (
+	// ErrBody is returned when the body of a Template cannot be encoded.
+	ErrBody = errors.New("unable to encode body")
+	// ErrMethod is returned when the method of a Template is invalid.
+	ErrMethod = errors.New("invalid method")
+)
+
+// Template is how the request for a URL is sent.
+type Template struct {
+	// Method is the HTTP method, such as POST or PUT. It is POST if empty and the
+	// request has a body, otherwise GET.
+	Method string
+	// Header is added to the default headers of the request.
+	Header http.Header
+	// Form is sent as a URL encoded form body.
+	Form url.Values
+	// QueryAsForm moves the query of the URL into the form body, so links
+	// to the pages of a search form can be crawled.
+	QueryAsForm bool
+	// JSON is encoded and sent as a JSON body if there is no form body.
+	JSON interface{}
+}
+
+// rule is a Template for the URLs that match the pattern.
+type rule struct {
+	pattern  *regexp.Regexp
+	template Template
+}
+
+// Method is a Requester that sends the request for a URL with the Template of the first
+// pattern that matches it, or as a GET request if none match. The requests are sent by Get
+// so its headers, timeout, redirects and cookies apply.
+type Method struct {
+	get   *get.Get
+	rules []rule
+}
+
+// Option is a functional option to modify the default Method instance.
+type Option func(m *Method)
+
+// WithGet replaces the default Get used to create and send the requests with the provided one.
+func WithGet(g *get.Get) Option {
+	return func(m *Method) {
+		m.get = g
+	}
+}
+
+// WithTemplate sends the requests for the URLs that match the pattern with the Template,
+// the patterns are matched in the order they are provided.
+func WithTemplate(pattern *regexp.Regexp, template Template) Option {
+	return func(m *Method) {
+		m.rules = append(m.rules, rule{pattern: pattern, template: template})
+	}
+}
+
+// New initializes a new Method Requester.
+func New(options ...Option) *Method {
+	m := &Method{
+		get: get.New(),
+	}
+
+	for _, opt := range options {
+		opt(m)
+	}
+
+	return m
+}
+
+// Request returns a HTTP request for the URL with the Template that matches it. A given body
+// replaces the body of the Template, a request with a body is sent with a POST request if the
+// Template has no method.
+func (m *Method) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	req, err := m.get.Request(ctx, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	template, ok := m.match(req.URL)
+	if !ok && body == nil {
+		return req, nil
+	}
+
+	u := *req.URL
+	contentType := ""
+
+	if body == nil {
+		body, contentType, err = encode(template, &u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	method := template.Method
+	if method == "" && body != nil {
+		method = http.MethodPost
+	}
+
+	templated, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMethod, err)
+	}
+
+	templated.Header = req.Header
+
+	for key, values := range template.Header {
+		for _, value := range values {
+			templated.Header.Add(key, value)
+		}
+	}
+
+	if contentType != "" && templated.Header.Get("Content-Type") == "" {
+		templated.Header.Set("Content-Type", contentType)
+	}
+
+	return templated, nil
+}
+
+// Do sends a HTTP request with Get and returns the response.
+func (m *Method) Do(req *http.Request) (*page.Response, error) {
+	return m.get.Do(req)
+}
+
+// match returns the Template of the first pattern that matches the URL.
+func (m *Method) match(u *url.URL) (Template, bool) {
+	for _, r := range m.rules {
+		if r.pattern.MatchString(u.String()) {
+			return r.template, true
+		}
+	}
+
+	return Template{}, false
+}
+
+// encode returns the body of the Template and its content type, the query
+// of the URL is removed if it is moved into the form body.
+func encode(template Template, u *url.URL) (io.Reader, string, error) {
+	form := url.Values{}
+
+	for key, values := range template.Form {
+		form[key] = append(form[key], values...)
+	}
+
+	if template.QueryAsForm {
+		for key, values := range u.Query() {
+			form[key] = append(form[key], values...)
+		}
+
+		u.RawQuery = ""
+	}
+
+	switch {
+	case len(form) > 0:
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+	case template.JSON != nil:
+		b, err := json.Marshal(template.JSON)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrBody, err)
+		}
+
+		return bytes.NewReader(b), "application/json", nil
+	default:
+		return nil, "", nil
+	}
+}
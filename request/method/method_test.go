@@ -0,0 +1,294 @@
+package method
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/request/get"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		want *Method
+	}{
+		{
+			name: "expect Method Requester to initialize",
+			want: &Method{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			if !cmp.Equal(got, tt.want, cmpopts.IgnoreUnexported(Method{})) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.IgnoreUnexported(Method{})))
+			}
+		})
+	}
+}
+
+func TestMethod_Request(t *testing.T) {
+	search := regexp.MustCompile(`^http://localhost/search`)
+	api := regexp.MustCompile(`^http://localhost/api/`)
+	form := regexp.MustCompile(`^http://localhost/form`)
+	header := regexp.MustCompile(`^http://localhost/header`)
+
+	options := []Option{
+		WithGet(get.New(get.WithUserAgent("crawler/1.0"))),
+		WithTemplate(search, Template{
+			Method:      http.MethodPost,
+			Form:        map[string][]string{"lang": {"en"}},
+			QueryAsForm: true,
+		}),
+		WithTemplate(api, Template{
+			Method: http.MethodPut,
+			Header: http.Header{"Authorization": []string{"Bearer token"}},
+			JSON:   map[string]int{"page": 1},
+		}),
+		WithTemplate(form, Template{Form: map[string][]string{"lang": {"en"}}}),
+		WithTemplate(header, Template{Header: http.Header{"Accept": []string{"text/html"}}}),
+	}
+
+	tests := []struct {
+		name       string
+		givenURL   string
+		givenBody  string
+		wantMethod string
+		wantURL    string
+		wantHeader http.Header
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name:       "expect GET request given no matching template",
+			givenURL:   "http://localhost/page",
+			wantMethod: http.MethodGet,
+			wantURL:    "http://localhost/page",
+			wantHeader: http.Header{"User-Agent": []string{"crawler/1.0"}},
+			wantBody:   "",
+			wantErr:    nil,
+		},
+		{
+			name:       "expect form request given a form template",
+			givenURL:   "http://localhost/search?q=go&page=2",
+			wantMethod: http.MethodPost,
+			wantURL:    "http://localhost/search",
+			wantHeader: http.Header{
+				"User-Agent":   []string{"crawler/1.0"},
+				"Content-Type": []string{"application/x-www-form-urlencoded"},
+			},
+			wantBody: "lang=en&page=2&q=go",
+			wantErr:  nil,
+		},
+		{
+			name:       "expect POST request given a form template without a method",
+			givenURL:   "http://localhost/form",
+			wantMethod: http.MethodPost,
+			wantURL:    "http://localhost/form",
+			wantHeader: http.Header{
+				"User-Agent":   []string{"crawler/1.0"},
+				"Content-Type": []string{"application/x-www-form-urlencoded"},
+			},
+			wantBody: "lang=en",
+			wantErr:  nil,
+		},
+		{
+			name:       "expect GET request given a template without a method or body",
+			givenURL:   "http://localhost/header",
+			wantMethod: http.MethodGet,
+			wantURL:    "http://localhost/header",
+			wantHeader: http.Header{
+				"User-Agent": []string{"crawler/1.0"},
+				"Accept":     []string{"text/html"},
+			},
+			wantBody: "",
+			wantErr:  nil,
+		},
+		{
+			name:       "expect JSON request given a JSON template",
+			givenURL:   "http://localhost/api/items",
+			wantMethod: http.MethodPut,
+			wantURL:    "http://localhost/api/items",
+			wantHeader: http.Header{
+				"User-Agent":    []string{"crawler/1.0"},
+				"Authorization": []string{"Bearer token"},
+				"Content-Type":  []string{"application/json"},
+			},
+			wantBody: `{"page":1}`,
+			wantErr:  nil,
+		},
+		{
+			name:       "expect given body sent given a template",
+			givenURL:   "http://localhost/api/items",
+			givenBody:  `{"page":2}`,
+			wantMethod: http.MethodPut,
+			wantURL:    "http://localhost/api/items",
+			wantHeader: http.Header{
+				"User-Agent":    []string{"crawler/1.0"},
+				"Authorization": []string{"Bearer token"},
+			},
+			wantBody: `{"page":2}`,
+			wantErr:  nil,
+		},
+		{
+			name:       "expect POST request given a body and no matching template",
+			givenURL:   "http://localhost/page",
+			givenBody:  "a=b",
+			wantMethod: http.MethodPost,
+			wantURL:    "http://localhost/page",
+			wantHeader: http.Header{"User-Agent": []string{"crawler/1.0"}},
+			wantBody:   "a=b",
+			wantErr:    nil,
+		},
+		{
+			name:     "expect error given an invalid URL",
+			givenURL: "%",
+			wantErr:  get.ErrRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(options...)
+
+			var body io.Reader
+			if tt.givenBody != "" {
+				body = strings.NewReader(tt.givenBody)
+			}
+
+			got, err := m.Request(context.Background(), tt.givenURL, body)
+
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !cmp.Equal(got.Method, tt.wantMethod) {
+				t.Error(cmp.Diff(got.Method, tt.wantMethod))
+			}
+
+			if !cmp.Equal(got.URL.String(), tt.wantURL) {
+				t.Error(cmp.Diff(got.URL.String(), tt.wantURL))
+			}
+
+			if !cmp.Equal(got.Header, tt.wantHeader) {
+				t.Error(cmp.Diff(got.Header, tt.wantHeader))
+			}
+
+			var gotBody []byte
+
+			if got.Body != nil {
+				gotBody, err = ioutil.ReadAll(got.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !cmp.Equal(string(gotBody), tt.wantBody) {
+				t.Error(cmp.Diff(string(gotBody), tt.wantBody))
+			}
+		})
+	}
+}
+
+func TestMethod_Request_Error(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenTemplate Template
+		wantErr       error
+	}{
+		{
+			name:          "expect error given a body that cannot be encoded",
+			givenTemplate: Template{Method: http.MethodPost, JSON: math.Inf(1)},
+			wantErr:       ErrBody,
+		},
+		{
+			name:          "expect error given an invalid method",
+			givenTemplate: Template{Method: "BAD METHOD"},
+			wantErr:       ErrMethod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(WithTemplate(regexp.MustCompile(`.*`), tt.givenTemplate))
+
+			_, err := m.Request(context.Background(), "http://localhost", nil)
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestMethod_Do(t *testing.T) {
+	handler := testutil.Handler{
+		Pattern: "/",
+		HandlerFunc: func(rw http.ResponseWriter, rr *http.Request) {
+			body, _ := ioutil.ReadAll(rr.Body)
+
+			_, _ = rw.Write([]byte(rr.Method + " " + string(body)))
+		},
+	}
+
+	tests := []struct {
+		name          string
+		givenTemplate Template
+		want          string
+	}{
+		{
+			name:          "expect form sent given a POST template",
+			givenTemplate: Template{Method: http.MethodPost, Form: map[string][]string{"q": {"go"}}},
+			want:          "POST q=go",
+		},
+		{
+			name:          "expect JSON sent given a PUT template",
+			givenTemplate: Template{Method: http.MethodPut, JSON: []string{"go"}},
+			want:          `PUT ["go"]`,
+		},
+		{
+			name:          "expect form sent with POST given a template without a method",
+			givenTemplate: Template{Form: map[string][]string{"q": {"go"}}},
+			want:          "POST q=go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := testutil.NewTestServer(handler)
+			defer ts.Close()
+
+			m := New(WithTemplate(regexp.MustCompile(`.*`), tt.givenTemplate))
+
+			req, err := m.Request(context.Background(), "http://127.0.0.1:8080/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := m.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			got, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(string(got), tt.want) {
+				t.Error(cmp.Diff(string(got), tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy decides whether a request is sent again after it fails.
+type RetryPolicy interface {
+	// Retry returns how long to wait before sending the request again given the
+	// number of attempts so far and the response or error of the last attempt,
+	// it reports false if the request is not sent again.
+	Retry(attempt int, resp *page.Response, err error) (time.Duration, bool)
+}
+
+// RetryPolicyFunc is an adapter to use an ordinary function as a RetryPolicy.
+type RetryPolicyFunc func(attempt int, resp *page.Response, err error) (time.Duration, bool)
+
+// Retry calls f(attempt, resp, err).
+func (f RetryPolicyFunc) Retry(attempt int, resp *page.Response, err error) (time.Duration, bool) {
+	return f(attempt, resp, err)
+}
+
+// NoRetry is a RetryPolicy that never sends a request again.
+func NoRetry() RetryPolicy {
+	return RetryPolicyFunc(func(_ int, _ *page.Response, _ error) (time.Duration, bool) {
+		return 0, false
+	})
+}
+
+// RetryOption is a functional option to modify the default RetryWithBackoff policy.
+type RetryOption func(b *backoff)
+
+// RetryStatuses replaces the status codes that are retried,
+// by default 429, 500, 502, 503 and 504 are retried.
+func RetryStatuses(codes ...int) RetryOption {
+	return func(b *backoff) {
+		b.statuses = map[int]bool{}
+
+		for _, code := range codes {
+			b.statuses[code] = true
+		}
+	}
+}
+
+// RetryErrors replaces the function that reports whether a request error
+// is retried, by default errors that are IsTransient are retried.
+func RetryErrors(retryable func(err error) bool) RetryOption {
+	return func(b *backoff) {
+		b.retryable = retryable
+	}
+}
+
+// backoff is a RetryPolicy with exponential backoff and jitter.
+type backoff struct {
+	maxAttempts int
+	base        time.Duration
+	max         time.Duration
+	statuses    map[int]bool
+	retryable   func(err error) bool
+}
+
+// RetryWithBackoff is a RetryPolicy that sends a request up to maxAttempts times while
+// it fails with a retryable status code or error. The wait before each retry doubles
+// from base up to max, with a random jitter of up to half the wait.
+func RetryWithBackoff(maxAttempts int, base, max time.Duration, options ...RetryOption) RetryPolicy {
+	b := &backoff{
+		maxAttempts: maxAttempts,
+		base:        base,
+		max:         max,
+		statuses: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+		retryable: IsTransient,
+	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
+}
+
+// Retry implements RetryPolicy.
+func (b *backoff) Retry(attempt int, resp *page.Response, err error) (time.Duration, bool) {
+	if attempt >= b.maxAttempts {
+		return 0, false
+	}
+
+	switch {
+	case err != nil && !b.retryable(err):
+		return 0, false
+	case err == nil && (resp == nil || !b.statuses[resp.StatusCode]):
+		return 0, false
+	}
+
+	wait := b.base
+	for i := 1; i < attempt && wait < b.max; i++ {
+		wait *= 2
+	}
+
+	if wait > b.max {
+		wait = b.max
+	}
+
+	if half := int64(wait / 2); half > 0 {
+		wait = wait/2 + time.Duration(rand.Int63n(half+1))
+	}
+
+	return wait, true
+}
+
+// IsTransient reports whether a request error is likely to succeed if the request
+// is sent again: timeouts, temporary DNS failures and refused, reset or closed connections.
+func IsTransient(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// RetryError is the error passed to the Logger when a request is sent again,
+// it holds the status code or error of the failed attempt.
+type RetryError struct {
+	URL        *url.URL
+	Attempt    int
+	Wait       time.Duration
+	StatusCode int
+	Err        error
+}
+
+// Error implements the error interface.
+func (e *RetryError) Error() string {
+	cause := fmt.Sprintf("status %d", e.StatusCode)
+	if e.Err != nil {
+		cause = e.Err.Error()
+	}
+
+	return fmt.Sprintf("retrying %s in %v after attempt %d: %s", e.URL, e.Wait, e.Attempt, cause)
+}
+
+// Unwrap returns the error of the failed attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
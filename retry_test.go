@@ -0,0 +1,323 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRetryWithBackoff_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenOptions []RetryOption
+		givenAttempt int
+		givenResp    *page.Response
+		givenErr     error
+		wantOK       bool
+		wantMin      time.Duration
+		wantMax      time.Duration
+	}{
+		{
+			name:         "expect retry given a retryable status",
+			givenAttempt: 1,
+			givenResp:    &page.Response{StatusCode: http.StatusServiceUnavailable},
+			wantOK:       true,
+			wantMin:      50 * time.Millisecond,
+			wantMax:      100 * time.Millisecond,
+		},
+		{
+			name:         "expect wait doubled given a later attempt",
+			givenAttempt: 3,
+			givenResp:    &page.Response{StatusCode: http.StatusTooManyRequests},
+			wantOK:       true,
+			wantMin:      200 * time.Millisecond,
+			wantMax:      400 * time.Millisecond,
+		},
+		{
+			name:         "expect wait capped at max",
+			givenAttempt: 4,
+			givenResp:    &page.Response{StatusCode: http.StatusBadGateway},
+			wantOK:       true,
+			wantMin:      250 * time.Millisecond,
+			wantMax:      500 * time.Millisecond,
+		},
+		{
+			name:         "expect no retry given max attempts",
+			givenAttempt: 5,
+			givenResp:    &page.Response{StatusCode: http.StatusServiceUnavailable},
+			wantOK:       false,
+		},
+		{
+			name:         "expect no retry given a successful response",
+			givenAttempt: 1,
+			givenResp:    &page.Response{StatusCode: http.StatusOK},
+			wantOK:       false,
+		},
+		{
+			name:         "expect no retry given a status that is not retryable",
+			givenAttempt: 1,
+			givenResp:    &page.Response{StatusCode: http.StatusNotFound},
+			wantOK:       false,
+		},
+		{
+			name:         "expect retry given a custom retryable status",
+			givenOptions: []RetryOption{RetryStatuses(http.StatusNotFound)},
+			givenAttempt: 1,
+			givenResp:    &page.Response{StatusCode: http.StatusNotFound},
+			wantOK:       true,
+			wantMin:      50 * time.Millisecond,
+			wantMax:      100 * time.Millisecond,
+		},
+		{
+			name:         "expect retry given a transient error",
+			givenAttempt: 1,
+			givenErr:     syscall.ECONNRESET,
+			wantOK:       true,
+			wantMin:      50 * time.Millisecond,
+			wantMax:      100 * time.Millisecond,
+		},
+		{
+			name:         "expect no retry given an error that is not transient",
+			givenAttempt: 1,
+			givenErr:     errTest,
+			wantOK:       false,
+		},
+		{
+			name: "expect retry given a custom retryable error",
+			givenOptions: []RetryOption{RetryErrors(func(err error) bool {
+				return errors.Is(err, errTest)
+			})},
+			givenAttempt: 1,
+			givenErr:     errTest,
+			wantOK:       true,
+			wantMin:      50 * time.Millisecond,
+			wantMax:      100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryWithBackoff(5, 100*time.Millisecond, 500*time.Millisecond, tt.givenOptions...)
+
+			got, ok := policy.Retry(tt.givenAttempt, tt.givenResp, tt.givenErr)
+			if !cmp.Equal(ok, tt.wantOK) {
+				t.Error(cmp.Diff(ok, tt.wantOK))
+			}
+
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("expected wait between %v and %v, got %v", tt.wantMin, tt.wantMax, got)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		givenErr error
+		want     bool
+	}{
+		{
+			name:     "expect transient given a refused connection",
+			givenErr: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED},
+			want:     true,
+		},
+		{
+			name:     "expect transient given a reset connection",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: syscall.ECONNRESET},
+			want:     true,
+		},
+		{
+			name:     "expect transient given a closed connection",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: io.EOF},
+			want:     true,
+		},
+		{
+			name:     "expect transient given a timeout",
+			givenErr: &net.DNSError{Err: "timeout", IsTimeout: true},
+			want:     true,
+		},
+		{
+			name:     "expect not transient given a host that does not exist",
+			givenErr: &net.DNSError{Err: "no such host", IsNotFound: true},
+			want:     false,
+		},
+		{
+			name:     "expect not transient given a cancelled request",
+			givenErr: &url.Error{Op: "Get", URL: "http://localhost", Err: context.Canceled},
+			want:     false,
+		},
+		{
+			name:     "expect not transient given any other error",
+			givenErr: errTest,
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsTransient(tt.givenErr)
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithRetryPolicy(t *testing.T) {
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.URL.Path == "" {
+			return []*url.URL{testutil.URLMustParse("http://localhost/a")}, nil
+		}
+
+		return nil, nil
+	})
+
+	tests := []struct {
+		name        string
+		givenPolicy RetryPolicy
+		givenSteps  []mockStep
+		want        *Report
+		wantErr     error
+		wantRetries int
+	}{
+		{
+			name:        "expect URL crawled once it succeeds",
+			givenPolicy: RetryWithBackoff(3, time.Millisecond, 10*time.Millisecond),
+			givenSteps: []mockStep{
+				{Status: http.StatusServiceUnavailable},
+				{Err: syscall.ECONNRESET},
+				{Status: http.StatusOK},
+			},
+			want: &Report{
+				Visited: []*url.URL{testutil.URLMustParse("http://localhost"), testutil.URLMustParse("http://localhost/a")},
+				Retried: 2,
+			},
+			wantErr:     nil,
+			wantRetries: 2,
+		},
+		{
+			name:        "expect last response given attempts exhausted",
+			givenPolicy: RetryWithBackoff(2, time.Millisecond, 10*time.Millisecond),
+			givenSteps: []mockStep{
+				{Status: http.StatusServiceUnavailable},
+				{Status: http.StatusServiceUnavailable},
+			},
+			want: &Report{
+				Visited: []*url.URL{testutil.URLMustParse("http://localhost"), testutil.URLMustParse("http://localhost/a")},
+				Retried: 1,
+			},
+			wantErr:     nil,
+			wantRetries: 1,
+		},
+		{
+			name:        "expect requester error given attempts exhausted",
+			givenPolicy: RetryWithBackoff(2, time.Millisecond, 10*time.Millisecond),
+			givenSteps: []mockStep{
+				{Err: syscall.ECONNRESET},
+				{Err: syscall.ECONNRESET},
+			},
+			want: &Report{
+				Visited: []*url.URL{testutil.URLMustParse("http://localhost")},
+				Retried: 1,
+			},
+			wantErr:     ErrRequester,
+			wantRetries: 1,
+		},
+		{
+			name:        "expect no retry given the default policy",
+			givenPolicy: NoRetry(),
+			givenSteps: []mockStep{
+				{Err: syscall.ECONNRESET},
+			},
+			want: &Report{
+				Visited: []*url.URL{testutil.URLMustParse("http://localhost")},
+			},
+			wantErr:     ErrRequester,
+			wantRetries: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []error
+
+			c := New(
+				WithConcurrency(1),
+				WithBackoff(0, 0),
+				WithRetryPolicy(tt.givenPolicy),
+				WithRequester(&mockStepRequester{GivenSteps: map[string][]mockStep{"/a": tt.givenSteps}}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{GivenErrors: &logged}),
+			)
+
+			got, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost"))
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(got, tt.want, ignoreStats) {
+				t.Error(cmp.Diff(got, tt.want, ignoreStats))
+			}
+
+			var retries int
+
+			for _, err := range logged {
+				var retryErr *RetryError
+				if errors.As(err, &retryErr) {
+					retries++
+				}
+			}
+
+			if !cmp.Equal(retries, tt.wantRetries) {
+				t.Error(cmp.Diff(retries, tt.wantRetries))
+			}
+		})
+	}
+}
+
+// mockStep is the status or error of a single request.
+type mockStep struct {
+	Status int
+	Err    error
+}
+
+// mockStepRequester responds to each path with its steps in order, then with 200 OK.
+type mockStepRequester struct {
+	GivenSteps map[string][]mockStep
+	mu         sync.Mutex
+	sent       int
+}
+
+func (m *mockStepRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m *mockStepRequester) Do(req *http.Request) (*page.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent++
+
+	step := mockStep{Status: http.StatusOK}
+
+	if steps := m.GivenSteps[req.URL.Path]; len(steps) > 0 {
+		step = steps[0]
+		m.GivenSteps[req.URL.Path] = steps[1:]
+	}
+
+	if step.Err != nil {
+		return nil, step.Err
+	}
+
+	return &page.Response{Request: req, URL: req.URL, StatusCode: step.Status}, nil
+}
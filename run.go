@@ -0,0 +1,372 @@
+package crawler
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+)
+
+// run is the state of a single crawl, so a Crawler can be reused
+// without one crawl affecting another.
+type run struct {
+	ctx     context.Context
+	queue   *queue
+	results chan<- Result
+
+	mu      *sync.Mutex
+	errs    Errors
+	crawled int
+	retried int
+	visited []*url.URL
+	codes   []int
+	sizes   []*int64
+	usage   *usage
+	hosts   map[string]*usage
+	refused error
+	pending map[*page.Link]*CrawlError
+
+	statuses  map[int]int
+	responses map[string]int
+	latencies *reservoir
+	end       time.Time
+
+	checkpointMu *sync.Mutex
+}
+
+// newRun initializes a new run that schedules links with the queue,
+// the results channel is optional.
+func newRun(ctx context.Context, q *queue, results chan<- Result) *run {
+	return &run{
+		ctx:     ctx,
+		queue:   q,
+		results: results,
+		mu:      &sync.Mutex{},
+		usage:   &usage{start: time.Now()},
+		hosts:   map[string]*usage{},
+		pending: map[*page.Link]*CrawlError{},
+
+		statuses:  map[int]int{},
+		responses: map[string]int{},
+		latencies: newReservoir(nil, 0),
+
+		checkpointMu: &sync.Mutex{},
+	}
+}
+
+// seed pushes the seed URLs to the queue with a scope of their hosts.
+func (r *run) seed(seeds []*url.URL) error {
+	scope := page.NewScope(seeds...)
+	links := make([]*page.Link, 0, len(seeds))
+
+	for _, u := range seeds {
+		link := page.NewLink(u)
+		link.Scope = scope
+
+		links = append(links, link)
+	}
+
+	_, err := r.queue.push(links...)
+
+	return err
+}
+
+// fail records the error and reports whether the policy aborts the run.
+func (r *run) fail(err *CrawlError, policy FailurePolicy) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+
+	return policy.Abort(len(r.errs), r.crawled)
+}
+
+// count records that a URL has been crawled.
+func (r *run) count() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.crawled++
+}
+
+// retry records that a request has been retried, the error of the failed
+// attempt is kept until the retry is crawled.
+func (r *run) retry(retry *page.Link, err *CrawlError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retried++
+	r.pending[retry] = err
+}
+
+// attempted returns the error of the failed attempt of a retried link, nil if the
+// link is not a retry. The error is no longer kept once it has been returned.
+func (r *run) attempted(link *page.Link) *CrawlError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.pending[link]
+	delete(r.pending, link)
+
+	return err
+}
+
+// reserve reports whether a page can be requested from the host within the budgets
+// of the run and of the host, counting it against both if it can.
+func (r *run) reserve(host string, global, perHost budget) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	h, ok := r.hosts[host]
+	if !ok {
+		h = &usage{start: now}
+		r.hosts[host] = h
+	}
+
+	if err := global.exhausted(r.usage, now); err != nil {
+		r.refused = err
+
+		return false
+	}
+
+	if perHost.exhausted(h, now) != nil {
+		return false
+	}
+
+	r.usage.pages++
+	h.pages++
+
+	return true
+}
+
+// read records the bytes read from a response body of the host in the bytes read of the response.
+func (r *run) read(host string, read *int64, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	*read += int64(n)
+	r.usage.bytes += int64(n)
+
+	if h, ok := r.hosts[host]; ok {
+		h.bytes += int64(n)
+	}
+}
+
+// refusal returns why the budget of the run refused a link, or nil if it has not refused one.
+func (r *run) refusal() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.refused
+}
+
+// exhausted returns why the budget of the run has been used up, or nil if it has not.
+func (r *run) exhausted(global budget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return global.exhausted(r.usage, time.Now())
+}
+
+// visit records that a URL has received the response, the bytes read of
+// the response are counted as its body is read.
+func (r *run) visit(u *url.URL, resp *page.Response, read *int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.visited = append(r.visited, u)
+	r.codes = append(r.codes, resp.StatusCode)
+	r.sizes = append(r.sizes, read)
+	r.statuses[resp.StatusCode]++
+	r.responses[u.Hostname()]++
+	r.latencies.add(resp.Duration)
+}
+
+// restore sets the report and the usage of the budgets of a run resumed from a checkpoint.
+func (r *run) restore(report *Report, crawled int, errs Errors, used *usage, hosts map[string]*usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.visited = report.Visited
+	r.codes = make([]int, len(report.Visited))
+	r.sizes = make([]*int64, len(report.Visited))
+	r.retried = report.Retried
+	r.crawled = crawled
+	r.errs = errs
+	r.usage = used
+	r.hosts = hosts
+}
+
+// restoreStats sets the response counts and latency samples of a run resumed from a checkpoint.
+func (r *run) restoreStats(statuses map[int]int, responses map[string]int, latencies *reservoir) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statuses = statuses
+	r.responses = responses
+	r.latencies = latencies
+}
+
+// recorded returns copies of the response counts and latency samples of the run, the responses
+// of the unvisited URLs are left out of the counts and the bytes read of them are returned by host.
+func (r *run) recorded(unvisited map[url.URL]bool) (checkpointStats, map[string]int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make(map[int]int, len(r.statuses))
+	for code, n := range r.statuses {
+		statuses[code] = n
+	}
+
+	responses := make(map[string]int, len(r.responses))
+	for host, n := range r.responses {
+		responses[host] = n
+	}
+
+	read := map[string]int64{}
+
+	for i, u := range r.visited {
+		code := r.codes[i]
+		if !unvisited[*u] || code == 0 {
+			continue
+		}
+
+		read[u.Hostname()] += *r.sizes[i]
+
+		if statuses[code]--; statuses[code] == 0 {
+			delete(statuses, code)
+		}
+
+		if responses[u.Hostname()]--; responses[u.Hostname()] == 0 {
+			delete(responses, u.Hostname())
+		}
+	}
+
+	samples := make([]time.Duration, len(r.latencies.samples))
+	copy(samples, r.latencies.samples)
+
+	return checkpointStats{StatusCodes: statuses, Hosts: responses, Latencies: samples, Sampled: r.latencies.seen}, read
+}
+
+// used returns a copy of the usage of the budgets of the run and of each host.
+func (r *run) used() (usage, map[string]usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hosts := make(map[string]usage, len(r.hosts))
+	for host, h := range r.hosts {
+		hosts[host] = *h
+	}
+
+	return *r.usage, hosts
+}
+
+// remaining returns the time left of the max duration of the budget.
+func (r *run) remaining(global budget) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return global.duration - time.Since(r.usage.start)
+}
+
+// snapshot returns a copy of the report of the run.
+func (r *run) snapshot() (*Report, int, Errors) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{
+		Visited: make([]*url.URL, len(r.visited)),
+		Retried: r.retried,
+	}
+	copy(report.Visited, r.visited)
+
+	errs := make(Errors, len(r.errs))
+	copy(errs, r.errs)
+
+	return report, r.crawled, errs
+}
+
+// stats returns a snapshot of the progress of the run.
+func (r *run) stats() Stats {
+	queued := r.queue.len()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.statsLocked(queued)
+}
+
+func (r *run) statsLocked(queued int) Stats {
+	end := r.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	stats := Stats{
+		Responses:   len(r.visited),
+		Failures:    len(r.errs),
+		Retries:     r.retried,
+		Bytes:       r.usage.bytes,
+		StatusCodes: make(map[int]int, len(r.statuses)),
+		Hosts:       make(map[string]int, len(r.responses)),
+		Queued:      queued,
+		Elapsed:     end.Sub(r.usage.start),
+	}
+
+	for code, n := range r.statuses {
+		stats.StatusCodes[code] = n
+	}
+
+	for host, n := range r.responses {
+		stats.Hosts[host] = n
+	}
+
+	stats.LatencyP50, stats.LatencyP95 = latencies(r.latencies.samples)
+
+	return stats
+}
+
+// emit sends the result to the results channel, blocking until it
+// is received or the context is done.
+func (r *run) emit(result Result) {
+	if r.results == nil {
+		return
+	}
+
+	select {
+	case r.results <- result:
+	case <-r.ctx.Done():
+	}
+}
+
+// result finishes the run and returns its report and error.
+func (r *run) result() (*Report, error) {
+	queued := r.queue.len()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.end.IsZero() {
+		r.end = time.Now()
+	}
+
+	report := &Report{
+		Visited: r.visited,
+		Retried: r.retried,
+		Stats:   r.statsLocked(queued),
+	}
+
+	if reason, cause := r.queue.stopped(); reason != nil {
+		return report, &StopError{Reason: reason, Cause: cause, Errors: r.errs}
+	}
+
+	if len(r.errs) == 0 {
+		return report, nil
+	}
+
+	return report, r.errs
+}
@@ -1,10 +1,9 @@
 package html
 
 import (
-	"io"
-	"net/http"
 	"net/url"
 
+	"github.com/clarke94/crawler/page"
 	"golang.org/x/net/html"
 )
 
@@ -16,13 +15,13 @@ func New() *HTML {
 	return &HTML{}
 }
 
-// Scrape extracts all HTML URLs from a reader.
-func (o *HTML) Scrape(req *http.Request, closer io.ReadCloser) ([]*url.URL, error) {
-	defer closer.Close()
+// Scrape extracts all HTML URLs from a response body.
+func (o *HTML) Scrape(_ *page.Link, resp *page.Response) ([]*url.URL, error) {
+	defer resp.Body.Close()
 
 	var links []*url.URL
 
-	z := html.NewTokenizer(closer)
+	z := html.NewTokenizer(resp.Body)
 
 	for {
 		tt := z.Next()
@@ -47,7 +46,7 @@ func (o *HTML) Scrape(req *http.Request, closer io.ReadCloser) ([]*url.URL, erro
 					continue
 				}
 
-				u := req.URL.ResolveReference(v)
+				u := resp.URL.ResolveReference(v)
 
 				links = append(links, u)
 			}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -95,7 +96,13 @@ func TestHTML_Scrape(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			o := &HTML{}
-			got, err := o.Scrape(tt.givenRequest, tt.givenCloser)
+			resp := &page.Response{
+				Request: tt.givenRequest,
+				URL:     tt.givenRequest.URL,
+				Body:    tt.givenCloser,
+			}
+
+			got, err := o.Scrape(page.NewLink(tt.givenRequest.URL), resp)
 
 			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
 				t.Fatal(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
@@ -0,0 +1,124 @@
+package crawler
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// maxSamples is the number of response durations kept to estimate the latency percentiles.
+const maxSamples = 1024
+
+// Stats is a snapshot of the progress of a crawl.
+type Stats struct {
+	// Responses is the number of requests that received a response.
+	Responses int
+	// Failures is the number of URLs that failed.
+	Failures int
+	// Retries is the number of requests sent again by the RetryPolicy.
+	Retries int
+	// Bytes is the number of bytes read from response bodies.
+	Bytes int64
+	// StatusCodes is the number of responses for each status code.
+	StatusCodes map[int]int
+	// Hosts is the number of responses from each host.
+	Hosts map[string]int
+	// LatencyP50 is the median duration of a response, estimated from a sample of the responses.
+	LatencyP50 time.Duration
+	// LatencyP95 is the 95th percentile duration of a response, estimated from a sample of the responses.
+	LatencyP95 time.Duration
+	// Queued is the number of URLs waiting to be crawled.
+	Queued int
+	// Elapsed is the time since the crawl started, or the time it took once it has finished.
+	Elapsed time.Duration
+}
+
+// Stats returns a snapshot of the progress of the current crawl, or of the
+// last crawl once it has finished. It is safe to call while crawling.
+func (c *Crawler) Stats() Stats {
+	c.mu.RLock()
+	r := c.current
+	c.mu.RUnlock()
+
+	if r == nil {
+		return Stats{StatusCodes: map[int]int{}, Hosts: map[string]int{}}
+	}
+
+	return r.stats()
+}
+
+// track makes the run the current crawl of the Crawler for Stats.
+func (c *Crawler) track(r *run) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.current = r
+}
+
+// reservoir is a uniform random sample of up to maxSamples response durations, so the
+// latency percentiles of a crawl of any size are estimated in a fixed amount of memory.
+// The percentiles are exact until more than maxSamples durations have been added.
+type reservoir struct {
+	samples []time.Duration
+	seen    int
+	rand    *rand.Rand
+}
+
+// newReservoir initializes a new reservoir with the samples of durations seen so far.
+func newReservoir(samples []time.Duration, seen int) *reservoir {
+	if seen < len(samples) {
+		seen = len(samples)
+	}
+
+	if len(samples) > maxSamples {
+		samples = samples[:maxSamples]
+	}
+
+	return &reservoir{
+		samples: samples,
+		seen:    seen,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// add adds the duration to the sample, replacing a random sample once it is full.
+func (r *reservoir) add(d time.Duration) {
+	r.seen++
+
+	if len(r.samples) < maxSamples {
+		r.samples = append(r.samples, d)
+
+		return
+	}
+
+	if i := r.rand.Intn(r.seen); i < maxSamples {
+		r.samples[i] = d
+	}
+}
+
+// percentile returns the nearest rank percentile p, between 0 and 1, of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sorted[i]
+}
+
+// latencies returns the p50 and p95 of the durations.
+func latencies(durations []time.Duration) (p50, p95 time.Duration) {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return percentile(sorted, 0.5), percentile(sorted, 0.95)
+}
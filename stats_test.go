@@ -0,0 +1,255 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// ignoreStats ignores the stats of a Report, which include timings.
+var ignoreStats = cmpopts.IgnoreFields(Report{}, "Stats")
+
+func TestLatencies(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenDurations []time.Duration
+		wantP50        time.Duration
+		wantP95        time.Duration
+	}{
+		{
+			name:           "expect zero given no durations",
+			givenDurations: nil,
+			wantP50:        0,
+			wantP95:        0,
+		},
+		{
+			name:           "expect the duration given a single duration",
+			givenDurations: []time.Duration{time.Second},
+			wantP50:        time.Second,
+			wantP95:        time.Second,
+		},
+		{
+			name: "expect nearest rank given unsorted durations",
+			givenDurations: []time.Duration{
+				10, 20, 30, 40, 50, 60, 70, 80, 90, 100,
+				110, 120, 130, 140, 150, 160, 170, 180, 200, 190,
+			},
+			wantP50: 100,
+			wantP95: 190,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotP50, gotP95 := latencies(tt.givenDurations)
+			if !cmp.Equal(gotP50, tt.wantP50) {
+				t.Error(cmp.Diff(gotP50, tt.wantP50))
+			}
+
+			if !cmp.Equal(gotP95, tt.wantP95) {
+				t.Error(cmp.Diff(gotP95, tt.wantP95))
+			}
+		})
+	}
+}
+
+func TestReservoir_add(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenSamples []time.Duration
+		givenSeen    int
+		givenAdded   int
+		wantSamples  int
+		wantSeen     int
+	}{
+		{
+			name:        "expect every duration given fewer durations than the max samples",
+			givenAdded:  10,
+			wantSamples: 10,
+			wantSeen:    10,
+		},
+		{
+			name:        "expect the max samples given more durations than the max samples",
+			givenAdded:  3 * maxSamples,
+			wantSamples: maxSamples,
+			wantSeen:    3 * maxSamples,
+		},
+		{
+			name:         "expect the samples to continue from the restored samples",
+			givenSamples: make([]time.Duration, maxSamples),
+			givenSeen:    2 * maxSamples,
+			givenAdded:   maxSamples,
+			wantSamples:  maxSamples,
+			wantSeen:     3 * maxSamples,
+		},
+		{
+			name:         "expect the max samples given more restored samples than the max samples",
+			givenSamples: make([]time.Duration, 2*maxSamples),
+			wantSamples:  maxSamples,
+			wantSeen:     2 * maxSamples,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReservoir(tt.givenSamples, tt.givenSeen)
+
+			for i := 0; i < tt.givenAdded; i++ {
+				r.add(time.Duration(i))
+			}
+
+			if !cmp.Equal(len(r.samples), tt.wantSamples) {
+				t.Error(cmp.Diff(len(r.samples), tt.wantSamples))
+			}
+
+			if !cmp.Equal(r.seen, tt.wantSeen) {
+				t.Error(cmp.Diff(r.seen, tt.wantSeen))
+			}
+		})
+	}
+}
+
+func TestCrawler_Stats(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenResponses map[string]*page.Response
+		givenCrawl     bool
+		want           Stats
+	}{
+		{
+			name:       "expect empty stats given no crawl",
+			givenCrawl: false,
+			want:       Stats{StatusCodes: map[int]int{}, Hosts: map[string]int{}},
+		},
+		{
+			name: "expect stats of the crawl",
+			givenResponses: map[string]*page.Response{
+				"/":  {StatusCode: http.StatusOK, Duration: 10 * time.Millisecond},
+				"/a": {StatusCode: http.StatusNotFound, Duration: 20 * time.Millisecond},
+				"/b": {StatusCode: http.StatusOK, Duration: 30 * time.Millisecond},
+			},
+			givenCrawl: true,
+			want: Stats{
+				Responses:   3,
+				Bytes:       10,
+				StatusCodes: map[int]int{http.StatusOK: 2, http.StatusNotFound: 1},
+				Hosts:       map[string]int{"localhost": 3},
+				LatencyP50:  20 * time.Millisecond,
+				LatencyP95:  30 * time.Millisecond,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(
+				WithConcurrency(1),
+				WithRequester(mockStatsRequester{GivenResponses: tt.givenResponses}),
+				WithScraper(mockBodyScraper{GivenPaths: []string{"/a", "/b"}}),
+				WithLogger(mockLogger{}),
+			)
+
+			if tt.givenCrawl {
+				report, err := c.CrawlContext(context.Background(), testutil.URLMustParse("http://localhost/"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !cmp.Equal(report.Stats, c.Stats()) {
+					t.Error(cmp.Diff(report.Stats, c.Stats()))
+				}
+			}
+
+			got := c.Stats()
+			if !cmp.Equal(got, tt.want, cmpopts.IgnoreFields(Stats{}, "Elapsed")) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.IgnoreFields(Stats{}, "Elapsed")))
+			}
+		})
+	}
+}
+
+func TestCrawler_Stats_ResumeFrom(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenResponses map[string]*page.Response
+		givenMax       int
+		want           Stats
+	}{
+		{
+			name: "expect stats of the crawl before the checkpoint given a resumed crawl",
+			givenResponses: map[string]*page.Response{
+				"/":  {StatusCode: http.StatusOK, Duration: 10 * time.Millisecond},
+				"/a": {StatusCode: http.StatusNotFound, Duration: 20 * time.Millisecond},
+				"/b": {StatusCode: http.StatusOK, Duration: 30 * time.Millisecond},
+			},
+			givenMax: 2,
+			want: Stats{
+				Responses:   3,
+				Bytes:       10,
+				StatusCodes: map[int]int{http.StatusOK: 2, http.StatusNotFound: 1},
+				Hosts:       map[string]int{"localhost": 3},
+				LatencyP50:  20 * time.Millisecond,
+				LatencyP95:  30 * time.Millisecond,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			newCrawler := func(options ...Option) *Crawler {
+				options = append([]Option{
+					WithConcurrency(1),
+					WithCheckpoint(dir, 0),
+					WithRequester(mockStatsRequester{GivenResponses: tt.givenResponses}),
+					WithScraper(mockBodyScraper{GivenPaths: []string{"/a", "/b"}}),
+					WithLogger(mockLogger{}),
+				}, options...)
+
+				return New(options...)
+			}
+
+			_, err := newCrawler(WithMaxPages(tt.givenMax)).CrawlContext(context.Background(), testutil.URLMustParse("http://localhost/"))
+			if !cmp.Equal(err, ErrBudget, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, ErrBudget, cmpopts.EquateErrors()))
+			}
+
+			report, err := newCrawler().ResumeFrom(context.Background(), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := report.Stats
+			if !cmp.Equal(got, tt.want, cmpopts.IgnoreFields(Stats{}, "Elapsed")) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.IgnoreFields(Stats{}, "Elapsed")))
+			}
+		})
+	}
+}
+
+// mockStatsRequester responds to each path with the status code and duration of its given response
+// and a body of the path repeated twice.
+type mockStatsRequester struct {
+	GivenResponses map[string]*page.Response
+}
+
+func (m mockStatsRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m mockStatsRequester) Do(req *http.Request) (*page.Response, error) {
+	given := m.GivenResponses[req.URL.Path]
+
+	return &page.Response{
+		Request:    req,
+		URL:        req.URL,
+		StatusCode: given.StatusCode,
+		Duration:   given.Duration,
+		Body:       io.NopCloser(strings.NewReader(strings.Repeat(req.URL.Path, 2))),
+	}, nil
+}
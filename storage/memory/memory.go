@@ -34,3 +34,10 @@ func (m *Memory) Write(visited *url.URL) error {
 
 	return nil
 }
+
+// Reset removes all visited urls from memory.
+func (m *Memory) Reset() error {
+	m.data = map[url.URL]bool{}
+
+	return nil
+}
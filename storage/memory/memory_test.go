@@ -92,6 +92,39 @@ func TestMemory_Write(t *testing.T) {
 	}
 }
 
+func TestMemory_Reset(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenData map[url.URL]bool
+		want      map[url.URL]bool
+		wantErr   error
+	}{
+		{
+			name: "expect no data after Reset",
+			givenData: map[url.URL]bool{
+				*testutil.URLMustParse("http://localhost"): true,
+			},
+			want: map[url.URL]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Memory{
+				data: tt.givenData,
+			}
+
+			err := m.Reset()
+			if !cmp.Equal(err, tt.wantErr, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(err, tt.wantErr, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(m.data, tt.want) {
+				t.Error(cmp.Diff(m.data, tt.want))
+			}
+		})
+	}
+}
+
 func TestNewMemory(t *testing.T) {
 	tests := []struct {
 		name string
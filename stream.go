@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/clarke94/crawler/page"
+)
+
+// Result is the outcome of crawling a single URL.
+type Result struct {
+	// URL is the crawled URL.
+	URL *url.URL
+	// Parent is the URL of the page the URL was found on, nil for a seed.
+	Parent *url.URL
+	// Depth is the number of links followed from the seed, zero for a seed.
+	Depth int
+	// StatusCode is the HTTP status code of the response, zero if no response was received.
+	StatusCode int
+	// Links are the URLs found on the page.
+	Links []*url.URL
+	// Err is the CrawlError if crawling the URL failed.
+	Err error
+}
+
+// newResult initializes a new Result for a crawled link.
+func newResult(link *page.Link, resp *page.Response, urls []*url.URL, err *CrawlError) Result {
+	result := Result{
+		URL:    link.URL,
+		Parent: link.Parent,
+		Depth:  link.Depth,
+		Links:  urls,
+	}
+
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+
+	if err != nil {
+		result.Err = err
+	}
+
+	return result
+}
+
+// Stream crawls the given seed URLs like CrawlContext and sends the Result of
+// every crawled URL on the returned channel as it arrives. The channel is
+// unbuffered so a slow receiver slows the crawl, it is closed once the crawl
+// is finished or the context is done.
+//
+// If the crawl fails or stops early, such as with ErrInvalidURL, ErrLogin or a
+// StopError, a final Result with no URL and the error is sent before the channel
+// is closed. It is not sent if the context is done before it is received.
+func (c *Crawler) Stream(ctx context.Context, seeds ...*url.URL) <-chan Result {
+	results := make(chan Result)
+
+	go func() {
+		defer close(results)
+
+		_, err := c.crawlSeeds(ctx, seeds, results)
+
+		// The errors of single URLs have already been sent with their Result.
+		if _, ok := err.(Errors); err == nil || ok {
+			return
+		}
+
+		select {
+		case results <- Result{Err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return results
+}
@@ -0,0 +1,127 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCrawler_Stream(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		if link.Depth > 0 {
+			return nil, errTest
+		}
+
+		return []*url.URL{testutil.URLMustParse("http://localhost/foo")}, nil
+	})
+
+	tests := []struct {
+		name         string
+		givenSeeds   []*url.URL
+		givenOptions []Option
+		want         []Result
+	}{
+		{
+			name:       "expect a result for every crawled URL",
+			givenSeeds: []*url.URL{testutil.URLMustParse("http://localhost")},
+			want: []Result{
+				{
+					URL:        testutil.URLMustParse("http://localhost"),
+					StatusCode: http.StatusOK,
+					Links:      []*url.URL{testutil.URLMustParse("http://localhost/foo")},
+				},
+				{
+					URL:        testutil.URLMustParse("http://localhost/foo"),
+					Parent:     testutil.URLMustParse("http://localhost"),
+					Depth:      1,
+					StatusCode: http.StatusOK,
+					Err:        ErrScraper,
+				},
+			},
+		},
+		{
+			name:       "expect a single error result given no seeds",
+			givenSeeds: nil,
+			want: []Result{
+				{Err: ErrInvalidURL},
+			},
+		},
+		{
+			name:       "expect a single error result given login error",
+			givenSeeds: []*url.URL{testutil.URLMustParse("http://localhost")},
+			givenOptions: []Option{WithLogin(func(ctx context.Context) error {
+				return errTest
+			})},
+			want: []Result{
+				{Err: ErrLogin},
+			},
+		},
+		{
+			name:         "expect a final error result given crawl stopped",
+			givenSeeds:   []*url.URL{testutil.URLMustParse("http://localhost")},
+			givenOptions: []Option{WithMaxPages(1)},
+			want: []Result{
+				{
+					URL:        testutil.URLMustParse("http://localhost"),
+					StatusCode: http.StatusOK,
+					Links:      []*url.URL{testutil.URLMustParse("http://localhost/foo")},
+				},
+				{Err: ErrBudget},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{
+				WithConcurrency(1),
+				WithRequester(mockRequester{GivenRequest: testRequest}),
+				WithScraper(testScraper),
+				WithLogger(mockLogger{}),
+			}, tt.givenOptions...)
+
+			c := New(options...)
+
+			var got []Result
+
+			for result := range c.Stream(context.Background(), tt.givenSeeds...) {
+				got = append(got, result)
+			}
+
+			if !cmp.Equal(got, tt.want, cmpopts.EquateErrors()) {
+				t.Error(cmp.Diff(got, tt.want, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestCrawler_Stream_Cancel(t *testing.T) {
+	testRequest := testutil.HTTPMustRequests(context.Background(), http.MethodGet, "http://localhost", nil)
+	testScraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+		return []*url.URL{link.URL.ResolveReference(&url.URL{Path: link.URL.Path + "/next"})}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := New(
+		WithConcurrency(1),
+		WithRequester(mockRequester{GivenRequest: testRequest}),
+		WithScraper(testScraper),
+		WithLogger(mockLogger{}),
+	)
+
+	results := c.Stream(ctx, testutil.URLMustParse("http://localhost"))
+
+	<-results
+	cancel()
+
+	for range results {
+	}
+}
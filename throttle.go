@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/clarke94/crawler/page"
+)
+
+const (
+	// defaultMinBackoff is the first delay given to a host when WithBackoff is not provided.
+	defaultMinBackoff = time.Second
+	// defaultMaxBackoff is the longest delay given to a host when WithBackoff is not provided.
+	defaultMaxBackoff = time.Minute
+	// latencyWeight is the weight of a response in the average latency of a host.
+	latencyWeight = 0.3
+	// slowLatency is how many times slower than its fastest average a host is
+	// before it is no longer sped up.
+	slowLatency = 2
+)
+
+// throttle slows down the crawl of hosts that respond with 429 Too Many Requests
+// or 503 Service Unavailable. The delay between the requests to a host is doubled
+// on each of these responses and halved on each response while the host responds
+// without errors and its latency has recovered.
+type throttle struct {
+	min   time.Duration
+	max   time.Duration
+	hosts map[string]*hostThrottle
+}
+
+// hostThrottle is the throttle state of a single host.
+type hostThrottle struct {
+	delay    time.Duration
+	until    time.Time
+	latency  time.Duration
+	baseline time.Duration
+}
+
+// newThrottle initializes a new throttle, a min of zero does not throttle hosts.
+func newThrottle(min, max time.Duration) *throttle {
+	return &throttle{
+		min:   min,
+		max:   max,
+		hosts: map[string]*hostThrottle{},
+	}
+}
+
+// ready returns when the next request can be sent to the host.
+func (t *throttle) ready(host string) time.Time {
+	h, ok := t.hosts[host]
+	if !ok {
+		return time.Time{}
+	}
+
+	return h.until
+}
+
+// dispatched spaces the next request to the host by its delay.
+func (t *throttle) dispatched(host string, now time.Time) {
+	h, ok := t.hosts[host]
+	if !ok || h.delay == 0 {
+		return
+	}
+
+	if next := now.Add(h.delay); next.After(h.until) {
+		h.until = next
+	}
+}
+
+// observe adjusts the delay of the host from the response.
+func (t *throttle) observe(host string, resp *page.Response, now time.Time) {
+	if t.min <= 0 {
+		return
+	}
+
+	h, ok := t.hosts[host]
+	if !ok {
+		h = &hostThrottle{}
+		t.hosts[host] = h
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		h.delay = t.clamp(h.delay * 2)
+
+		wait := h.delay
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now); retryAfter > wait {
+			wait = retryAfter
+		}
+
+		if until := now.Add(t.clamp(wait)); until.After(h.until) {
+			h.until = until
+		}
+
+		return
+	}
+
+	if h.latency == 0 {
+		h.latency = resp.Duration
+	} else {
+		h.latency = time.Duration(latencyWeight*float64(resp.Duration) + (1-latencyWeight)*float64(h.latency))
+	}
+
+	if h.baseline == 0 || h.latency < h.baseline {
+		h.baseline = h.latency
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError || h.latency > slowLatency*h.baseline {
+		return
+	}
+
+	if h.delay /= 2; h.delay < t.min {
+		h.delay = 0
+	}
+}
+
+// clamp limits the delay to between the min and max of the throttle.
+func (t *throttle) clamp(d time.Duration) time.Duration {
+	if d < t.min {
+		return t.min
+	}
+
+	if d > t.max {
+		return t.max
+	}
+
+	return d
+}
+
+// parseRetryAfter parses the seconds or HTTP date of a Retry-After header as the time to wait.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+
+	return 0
+}
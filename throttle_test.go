@@ -0,0 +1,251 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clarke94/crawler/internal/testutil"
+	"github.com/clarke94/crawler/page"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestThrottle_observe(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		givenResponses []*page.Response
+		wantDelay      time.Duration
+		wantReady      time.Time
+	}{
+		{
+			name: "expect no delay given successful responses",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusOK, Duration: time.Millisecond},
+			},
+			wantDelay: 0,
+			wantReady: time.Time{},
+		},
+		{
+			name: "expect min delay given too many requests",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests},
+			},
+			wantDelay: time.Second,
+			wantReady: now.Add(time.Second),
+		},
+		{
+			name: "expect delay doubled given repeated service unavailable",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusServiceUnavailable},
+				{StatusCode: http.StatusServiceUnavailable},
+				{StatusCode: http.StatusServiceUnavailable},
+			},
+			wantDelay: 4 * time.Second,
+			wantReady: now.Add(4 * time.Second),
+		},
+		{
+			name: "expect delay capped at max",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+			},
+			wantDelay: 10 * time.Second,
+			wantReady: now.Add(10 * time.Second),
+		},
+		{
+			name: "expect Retry-After seconds honoured",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"5"}}},
+			},
+			wantDelay: time.Second,
+			wantReady: now.Add(5 * time.Second),
+		},
+		{
+			name: "expect Retry-After date honoured",
+			givenResponses: []*page.Response{
+				{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{"Retry-After": []string{now.Add(3 * time.Second).Format(http.TimeFormat)}},
+				},
+			},
+			wantDelay: time.Second,
+			wantReady: now.Add(3 * time.Second),
+		},
+		{
+			name: "expect Retry-After capped at max",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3600"}}},
+			},
+			wantDelay: time.Second,
+			wantReady: now.Add(10 * time.Second),
+		},
+		{
+			name: "expect delay halved given the host recovers",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusOK, Duration: time.Millisecond},
+			},
+			wantDelay: 2 * time.Second,
+			wantReady: now.Add(4 * time.Second),
+		},
+		{
+			name: "expect delay removed once halved below min",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusOK, Duration: time.Millisecond},
+			},
+			wantDelay: 0,
+			wantReady: now.Add(time.Second),
+		},
+		{
+			name: "expect delay held given a server error",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusInternalServerError, Duration: time.Millisecond},
+			},
+			wantDelay: time.Second,
+			wantReady: now.Add(time.Second),
+		},
+		{
+			name: "expect delay held given a slow host",
+			givenResponses: []*page.Response{
+				{StatusCode: http.StatusOK, Duration: time.Millisecond},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusTooManyRequests},
+				{StatusCode: http.StatusOK, Duration: time.Second},
+			},
+			wantDelay: 2 * time.Second,
+			wantReady: now.Add(2 * time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := newThrottle(time.Second, 10*time.Second)
+
+			for _, resp := range tt.givenResponses {
+				th.observe("localhost", resp, now)
+			}
+
+			var gotDelay time.Duration
+			if h, ok := th.hosts["localhost"]; ok {
+				gotDelay = h.delay
+			}
+
+			if !cmp.Equal(gotDelay, tt.wantDelay) {
+				t.Error(cmp.Diff(gotDelay, tt.wantDelay))
+			}
+
+			gotReady := th.ready("localhost")
+			if !cmp.Equal(gotReady, tt.wantReady) {
+				t.Error(cmp.Diff(gotReady, tt.wantReady))
+			}
+		})
+	}
+}
+
+func TestCrawler_WithBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenMin   time.Duration
+		givenMax   time.Duration
+		givenFirst int
+		wantGap    time.Duration
+	}{
+		{
+			name:       "expect host delayed given too many requests",
+			givenMin:   100 * time.Millisecond,
+			givenMax:   time.Second,
+			givenFirst: http.StatusTooManyRequests,
+			wantGap:    100 * time.Millisecond,
+		},
+		{
+			name:       "expect host not delayed given successful response",
+			givenMin:   time.Second,
+			givenMax:   time.Second,
+			givenFirst: http.StatusOK,
+			wantGap:    0,
+		},
+		{
+			name:       "expect host not delayed given throttling disabled",
+			givenMin:   0,
+			givenMax:   0,
+			givenFirst: http.StatusTooManyRequests,
+			wantGap:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &mockTimedRequester{
+				GivenStatus: map[string]int{"/a": tt.givenFirst},
+			}
+
+			scraper := mockScraperFunc(func(link *page.Link) ([]*url.URL, error) {
+				if link.URL.Path == "" {
+					return []*url.URL{
+						testutil.URLMustParse("http://localhost/a"),
+						testutil.URLMustParse("http://localhost/b"),
+					}, nil
+				}
+
+				return nil, nil
+			})
+
+			c := New(
+				WithConcurrency(1),
+				WithBackoff(tt.givenMin, tt.givenMax),
+				WithRequester(requester),
+				WithScraper(scraper),
+				WithLogger(mockLogger{}),
+			)
+
+			if err := c.Crawl(testutil.URLMustParse("http://localhost")); err != nil {
+				t.Fatal(err)
+			}
+
+			gap := requester.sent["/b"].Sub(requester.sent["/a"])
+			if gap < tt.wantGap || (tt.wantGap == 0 && gap > 50*time.Millisecond) {
+				t.Errorf("expected a gap of %v between requests, got %v", tt.wantGap, gap)
+			}
+		})
+	}
+}
+
+// mockTimedRequester records when each path is requested and responds with the given status.
+type mockTimedRequester struct {
+	GivenStatus map[string]int
+	mu          sync.Mutex
+	sent        map[string]time.Time
+}
+
+func (m *mockTimedRequester) Request(ctx context.Context, rawURL string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, body)
+}
+
+func (m *mockTimedRequester) Do(req *http.Request) (*page.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.sent == nil {
+		m.sent = map[string]time.Time{}
+	}
+
+	m.sent[req.URL.Path] = time.Now()
+
+	status, ok := m.GivenStatus[req.URL.Path]
+	if !ok {
+		status = http.StatusOK
+	}
+
+	return &page.Response{Request: req, URL: req.URL, StatusCode: status}, nil
+}